@@ -0,0 +1,185 @@
+// Package assetgc removes stored assets that no company references, such as
+// screenshots left behind by failed generation runs.
+package assetgc
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"startupdose.com/cmd/server/database"
+	"startupdose.com/cmd/server/storage"
+)
+
+// Defaults used when Options leaves a field zero
+const (
+	DefaultGracePeriod      = 7 * 24 * time.Hour
+	DefaultQuarantinePrefix = "quarantine/"
+)
+
+// Actions recorded for each orphaned object
+const (
+	ActionDelete     = "delete"
+	ActionQuarantine = "quarantine"
+)
+
+// Options controls a collection run
+type Options struct {
+	// Prefixes to scan; defaults to storage.AssetPrefixes
+	Prefixes []string
+	// GracePeriod protects objects younger than this, which may belong to a run in progress
+	GracePeriod time.Duration
+	// QuarantinePrefix moves orphans under this prefix instead of deleting them
+	// Leave empty to delete
+	QuarantinePrefix string
+	// DryRun reports what would happen without changing anything
+	DryRun bool
+}
+
+// Orphan is an unreferenced object
+type Orphan struct {
+	Key          string    `json:"key"`
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"last_modified"`
+	Action       string    `json:"action"`
+	Error        string    `json:"error,omitempty"`
+}
+
+// Report summarizes a collection run
+type Report struct {
+	DryRun         bool      `json:"dry_run"`
+	Prefixes       []string  `json:"prefixes"`
+	GracePeriod    string    `json:"grace_period"`
+	Cutoff         time.Time `json:"cutoff"`
+	Scanned        int       `json:"scanned"`
+	Referenced     int       `json:"referenced"`
+	TooRecent      int       `json:"too_recent"`
+	Orphans        []Orphan  `json:"orphans"`
+	Removed        int       `json:"removed"`
+	Failed         int       `json:"failed"`
+	BytesReclaimed int64     `json:"bytes_reclaimed"`
+}
+
+// Run collects orphaned assets in the configured object store, using the
+// companies and assets tables as the set of live references
+func Run(ctx context.Context, opts Options) (*Report, error) {
+	store := storage.GetStore()
+	if store == nil {
+		return nil, fmt.Errorf("object storage not configured")
+	}
+
+	refs, err := referencedKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	return Collect(ctx, store, refs, opts)
+}
+
+// referencedKeys gathers the storage keys referenced by companies and assets
+func referencedKeys() (map[string]bool, error) {
+	urls, err := database.NewCompanyRepository().ListImageURLs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list company images: %w", err)
+	}
+	keys, err := database.NewAssetRepository().ListKeys()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list assets: %w", err)
+	}
+
+	refs := make(map[string]bool, len(urls)+len(keys))
+	for _, u := range urls {
+		if key, ok := storage.KeyFromURL(u); ok {
+			refs[key] = true
+		}
+	}
+	for _, key := range keys {
+		refs[key] = true
+	}
+	return refs, nil
+}
+
+// Collect lists objects under the configured prefixes and deletes or
+// quarantines those not in refs and older than the grace period
+func Collect(ctx context.Context, store storage.ObjectStore, refs map[string]bool, opts Options) (*Report, error) {
+	if len(opts.Prefixes) == 0 {
+		opts.Prefixes = storage.AssetPrefixes
+	}
+	if opts.GracePeriod <= 0 {
+		opts.GracePeriod = DefaultGracePeriod
+	}
+	if opts.QuarantinePrefix != "" && !strings.HasSuffix(opts.QuarantinePrefix, "/") {
+		opts.QuarantinePrefix += "/"
+	}
+
+	// An empty reference set almost always means the store URL changed or the
+	// lookup silently failed; refuse rather than wipe the bucket
+	if len(refs) == 0 && !opts.DryRun {
+		return nil, fmt.Errorf("no referenced assets found, refusing to collect (use a dry run to inspect)")
+	}
+
+	report := &Report{
+		DryRun:      opts.DryRun,
+		Prefixes:    opts.Prefixes,
+		GracePeriod: opts.GracePeriod.String(),
+		Cutoff:      time.Now().UTC().Add(-opts.GracePeriod),
+		Orphans:     []Orphan{},
+	}
+
+	action := ActionDelete
+	if opts.QuarantinePrefix != "" {
+		action = ActionQuarantine
+	}
+
+	for _, prefix := range opts.Prefixes {
+		objects, err := store.List(ctx, prefix)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", prefix, err)
+		}
+		sort.Slice(objects, func(i, j int) bool { return objects[i].Key < objects[j].Key })
+
+		for _, obj := range objects {
+			report.Scanned++
+
+			switch {
+			case refs[obj.Key]:
+				report.Referenced++
+				continue
+			case obj.LastModified.After(report.Cutoff):
+				report.TooRecent++
+				continue
+			}
+
+			orphan := Orphan{
+				Key:          obj.Key,
+				Size:         obj.Size,
+				LastModified: obj.LastModified,
+				Action:       action,
+			}
+
+			if !opts.DryRun {
+				var err error
+				if action == ActionQuarantine {
+					err = store.Move(ctx, obj.Key, opts.QuarantinePrefix+obj.Key)
+				} else {
+					err = store.Delete(ctx, obj.Key)
+				}
+				if err != nil {
+					log.Printf("WARNING: Failed to %s orphaned asset %s: %v\n", action, obj.Key, err)
+					orphan.Error = err.Error()
+					report.Failed++
+				} else {
+					report.Removed++
+					report.BytesReclaimed += obj.Size
+				}
+			}
+
+			report.Orphans = append(report.Orphans, orphan)
+		}
+	}
+
+	return report, nil
+}
@@ -0,0 +1,170 @@
+package budget
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrBudgetExceeded is returned when a spend cap has been reached
+var ErrBudgetExceeded = errors.New("LLM spend budget exceeded")
+
+// ModelPrice holds the USD price per one million tokens for a model
+type ModelPrice struct {
+	PromptPerMillion     float64 `json:"prompt"`
+	CompletionPerMillion float64 `json:"completion"`
+}
+
+// PriceTable maps a model name (or model name prefix) to its price
+type PriceTable map[string]ModelPrice
+
+// DefaultPrices are used when LLM_PRICE_TABLE is not set
+var DefaultPrices = PriceTable{
+	"gpt-4o-mini": {PromptPerMillion: 0.15, CompletionPerMillion: 0.60},
+	"gpt-4o":      {PromptPerMillion: 2.50, CompletionPerMillion: 10.00},
+}
+
+// ParsePriceTable parses a JSON price table such as
+// {"gpt-4o-mini":{"prompt":0.15,"completion":0.60}}
+// An empty string returns DefaultPrices
+func ParsePriceTable(raw string) (PriceTable, error) {
+	if strings.TrimSpace(raw) == "" {
+		return DefaultPrices, nil
+	}
+
+	var table PriceTable
+	if err := json.Unmarshal([]byte(raw), &table); err != nil {
+		return nil, fmt.Errorf("invalid price table: %w", err)
+	}
+	return table, nil
+}
+
+// Cost returns the USD cost of a call. Models are matched exactly first, then
+// by the longest matching prefix so dated snapshots like "gpt-4o-mini-2024-07-18"
+// resolve to "gpt-4o-mini". The bool is false when the model has no price.
+func (t PriceTable) Cost(model string, promptTokens, completionTokens int) (float64, bool) {
+	price, ok := t[model]
+	if !ok {
+		bestLen := 0
+		for name, p := range t {
+			if strings.HasPrefix(model, name) && len(name) > bestLen {
+				price, bestLen, ok = p, len(name), true
+			}
+		}
+	}
+	if !ok {
+		return 0, false
+	}
+
+	cost := float64(promptTokens)*price.PromptPerMillion/1e6 +
+		float64(completionTokens)*price.CompletionPerMillion/1e6
+	return cost, true
+}
+
+// Limits holds the spend caps in USD. A zero value disables that cap.
+type Limits struct {
+	DailyUSD   float64
+	MonthlyUSD float64
+}
+
+// SpendStore is the persistence needed by the Tracker
+type SpendStore interface {
+	SumCostSince(since time.Time) (float64, error)
+}
+
+// Status describes current spend against the configured caps
+type Status struct {
+	DailySpendUSD   float64   `json:"daily_spend_usd"`
+	DailyCapUSD     float64   `json:"daily_cap_usd"`
+	MonthlySpendUSD float64   `json:"monthly_spend_usd"`
+	MonthlyCapUSD   float64   `json:"monthly_cap_usd"`
+	DayStart        time.Time `json:"day_start"`
+	MonthStart      time.Time `json:"month_start"`
+	Exceeded        bool      `json:"exceeded"`
+	Reason          string    `json:"reason,omitempty"`
+}
+
+// Tracker enforces spend caps using recorded usage
+type Tracker struct {
+	store  SpendStore
+	limits Limits
+	now    func() time.Time
+}
+
+// NewTracker creates a new Tracker
+func NewTracker(store SpendStore, limits Limits) *Tracker {
+	return &Tracker{
+		store:  store,
+		limits: limits,
+		now:    time.Now,
+	}
+}
+
+// Status returns the current spend. Days and months are computed in UTC.
+func (t *Tracker) Status() (*Status, error) {
+	return t.status(0)
+}
+
+// status computes the current spend; reserveUSD is the estimated cost of calls
+// about to be made, and the caps are exceeded when it doesn't fit under them
+func (t *Tracker) status(reserveUSD float64) (*Status, error) {
+	now := t.now().UTC()
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	monthly, err := t.store.SumCostSince(monthStart)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum monthly spend: %w", err)
+	}
+	daily, err := t.store.SumCostSince(dayStart)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum daily spend: %w", err)
+	}
+
+	status := &Status{
+		DailySpendUSD:   daily,
+		DailyCapUSD:     t.limits.DailyUSD,
+		MonthlySpendUSD: monthly,
+		MonthlyCapUSD:   t.limits.MonthlyUSD,
+		DayStart:        dayStart,
+		MonthStart:      monthStart,
+	}
+
+	switch {
+	case t.limits.DailyUSD > 0 && daily >= t.limits.DailyUSD:
+		status.Exceeded = true
+		status.Reason = fmt.Sprintf("daily LLM spend cap of $%.2f reached ($%.4f spent)", t.limits.DailyUSD, daily)
+	case t.limits.MonthlyUSD > 0 && monthly >= t.limits.MonthlyUSD:
+		status.Exceeded = true
+		status.Reason = fmt.Sprintf("monthly LLM spend cap of $%.2f reached ($%.4f spent)", t.limits.MonthlyUSD, monthly)
+	case t.limits.DailyUSD > 0 && daily+reserveUSD > t.limits.DailyUSD:
+		status.Exceeded = true
+		status.Reason = fmt.Sprintf("daily LLM spend cap of $%.2f would be exceeded ($%.4f spent, $%.4f estimated for this request)", t.limits.DailyUSD, daily, reserveUSD)
+	case t.limits.MonthlyUSD > 0 && monthly+reserveUSD > t.limits.MonthlyUSD:
+		status.Exceeded = true
+		status.Reason = fmt.Sprintf("monthly LLM spend cap of $%.2f would be exceeded ($%.4f spent, $%.4f estimated for this request)", t.limits.MonthlyUSD, monthly, reserveUSD)
+	}
+
+	return status, nil
+}
+
+// Check returns ErrBudgetExceeded (wrapped with the reason) when a cap is reached
+func (t *Tracker) Check() (*Status, error) {
+	return t.CheckReserve(0)
+}
+
+// CheckReserve is like Check but also fails when spending estimateUSD more
+// would go over a cap, so a batch of concurrent calls is checked as a whole
+// before any of them starts
+func (t *Tracker) CheckReserve(estimateUSD float64) (*Status, error) {
+	status, err := t.status(estimateUSD)
+	if err != nil {
+		return nil, err
+	}
+	if status.Exceeded {
+		return status, fmt.Errorf("%w: %s", ErrBudgetExceeded, status.Reason)
+	}
+	return status, nil
+}
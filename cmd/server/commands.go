@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"startupdose.com/cmd/server/assetgc"
+	"startupdose.com/cmd/server/config"
+	"startupdose.com/cmd/server/igtoken"
+	"startupdose.com/cmd/server/insights"
+)
+
+// runCommand runs a maintenance subcommand instead of the HTTP server
+// Returns the process exit code
+func runCommand(cfg *config.Config, args []string) int {
+	switch {
+	case len(args) >= 2 && args[0] == "gc" && args[1] == "assets":
+		return runAssetGC(cfg, args[2:])
+	case len(args) >= 2 && args[0] == "collect" && args[1] == "insights":
+		return runCollectInsights(cfg, args[2:])
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command %q\n", args)
+		fmt.Fprintln(os.Stderr, "Usage: server [gc assets [flags] | collect insights [flags]]")
+		return 2
+	}
+}
+
+// runAssetGC implements "gc assets"
+func runAssetGC(cfg *config.Config, args []string) int {
+	fs := flag.NewFlagSet("gc assets", flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "report orphaned assets without removing them")
+	grace := fs.Duration("grace", assetgc.DefaultGracePeriod, "only collect objects older than this")
+	mode := fs.String("mode", cfg.AssetGCMode, "quarantine or delete")
+	quarantine := fs.String("quarantine-prefix", cfg.AssetGCQuarantinePrefix, "prefix orphans are moved under in quarantine mode")
+	timeout := fs.Duration("timeout", 30*time.Minute, "abort the run after this long")
+
+	if cfg.AssetGCGracePeriod != "" {
+		if d, err := time.ParseDuration(cfg.AssetGCGracePeriod); err == nil && d > 0 {
+			*grace = d
+		}
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	opts := assetgc.Options{
+		GracePeriod:      *grace,
+		QuarantinePrefix: *quarantine,
+		DryRun:           *dryRun,
+	}
+	switch *mode {
+	case assetgc.ActionQuarantine:
+		if opts.QuarantinePrefix == "" {
+			opts.QuarantinePrefix = assetgc.DefaultQuarantinePrefix
+		}
+	case assetgc.ActionDelete:
+		opts.QuarantinePrefix = ""
+	default:
+		fmt.Fprintf(os.Stderr, "Invalid -mode %q: use quarantine or delete\n", *mode)
+		return 2
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	report, err := assetgc.Run(ctx, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Asset garbage collection failed: %v\n", err)
+		return 1
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	enc.Encode(report)
+
+	if report.Failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+// runCollectInsights implements "collect insights", a single collection run
+// for deployments that schedule it instead of running it in the server
+func runCollectInsights(cfg *config.Config, args []string) int {
+	opts := insights.NewOptions(cfg)
+
+	fs := flag.NewFlagSet("collect insights", flag.ContinueOnError)
+	maxAge := fs.Duration("max-age", opts.MaxAge, "only collect posts published within this long")
+	timeout := fs.Duration("timeout", 10*time.Minute, "abort the run after this long")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	opts.MaxAge = *maxAge
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	// Use the stored token rather than IG_ACCESS_TOKEN, which may be stale
+	if err := igtoken.Init(cfg).Check(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "Instagram token check failed: %v\n", err)
+	}
+
+	report, err := insights.Collect(ctx, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Insights collection failed: %v\n", err)
+		return 1
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	enc.Encode(report)
+
+	if report.Failed > 0 {
+		return 1
+	}
+	return 0
+}
@@ -16,6 +16,16 @@ type Config struct {
 	// OpenAI
 	OpenAIAPIKey string
 
+	// LLM spend budgeting
+	LLMPriceTable       string
+	LLMDailyBudgetUSD   string
+	LLMMonthlyBudgetUSD string
+	LLMBudgetFailOpen   string
+
+	// Candidate generation
+	GenerationCandidates string
+	CandidateRubric      string
+
 	// Supabase
 	SupabaseURL         string
 	SupabaseKey         string
@@ -30,20 +40,84 @@ type Config struct {
 	HTTPMaxIdleConnsPerHost  string
 	HTTPMaxConnsPerHost      string
 
+	// Remote fetching
+	RemoteFetchTimeout      string
+	RemoteFetchMaxBytes     string
+	RemoteFetchMaxRedirects string
+
+	// Object storage
+	StorageBackend        string
+	PublicBaseURL         string
+	SupabaseStorageBucket string
+	LocalStorageDir       string
+	StoragePrivate        bool
+	StorageCDNBaseURL     string
+	StorageSignedURLTTL   string
+
+	// Asset garbage collection
+	AssetGCGracePeriod      string
+	AssetGCMode             string
+	AssetGCQuarantinePrefix string
+
 	// AWS S3
 	AWSRegion          string
 	AWSAccessKeyID     string
 	AWSSecretAccessKey string
 	S3BucketName       string
+	S3Endpoint         string
+	S3ForcePathStyle   bool
+
+	// Screenshots
+	ScreenshotProvider           string
+	ScreenshotOneAPIKey          string
+	ChromePath                   string
+	ScreenshotViewportWidth      string
+	ScreenshotViewportHeight     string
+	ScreenshotDeviceScaleFactor  string
+	ScreenshotFormat             string
+	ScreenshotDelay              string
+	ScreenshotHideSelectors      string
+	ScreenshotBlockCookieBanners string
+	ScreenshotBadTemplatesDir    string
+	ScreenshotBadTemplateHashes  string
 
-	// ScreenshotOne API
-	ScreenshotOneAPIKey string
+	// Image variants
+	ImageVariantWidths string
+	CWebPPath          string
 
 	// Instagram API
 	IGUserID         string
 	IGAccessToken    string
 	IGAPIVersion     string
 	IGPostingEnabled bool
+	IGPostFormat     string
+	IGStoryEnabled   bool
+
+	// Instagram token refresh
+	IGAppID              string
+	IGAppSecret          string
+	IGTokenRefreshBefore string
+	IGTokenCheckInterval string
+
+	// Instagram container polling
+	IGPollInitialDelay     string
+	IGPollMaxDelay         string
+	IGPollMaxAttempts      string
+	IGVideoPollMaxAttempts string
+
+	// Instagram insights
+	IGInsightsEnabled  bool
+	IGInsightsInterval string
+	IGInsightsMaxAge   string
+
+	// Instagram captions
+	IGCaptionTemplatesDir  string
+	IGCaptionHashtags      string
+	IGCaptionMaxHashtags   string
+	IGHashtagsFirstComment bool
+
+	// Notifications
+	NotifyWebhookURL string
 }
 
 // Load reads configuration from environment variables
@@ -58,6 +132,16 @@ func Load() *Config {
 		// OpenAI
 		OpenAIAPIKey: getEnv("OPENAI_API_KEY", ""),
 
+		// LLM spend budgeting
+		LLMPriceTable:       getEnv("LLM_PRICE_TABLE", ""),
+		LLMDailyBudgetUSD:   getEnv("LLM_DAILY_BUDGET_USD", ""),
+		LLMMonthlyBudgetUSD: getEnv("LLM_MONTHLY_BUDGET_USD", ""),
+		LLMBudgetFailOpen:   getEnv("LLM_BUDGET_FAIL_OPEN", "false"),
+
+		// Candidate generation
+		GenerationCandidates: getEnv("GENERATION_CANDIDATES", "3"),
+		CandidateRubric:      getEnv("CANDIDATE_RUBRIC", ""),
+
 		// Supabase
 		SupabaseURL:         getEnv("SUPABASE_URL", ""),
 		SupabaseKey:         getEnv("SUPABASE_KEY", ""),
@@ -72,20 +156,84 @@ func Load() *Config {
 		HTTPMaxIdleConnsPerHost: getEnv("HTTP_MAX_IDLE_CONNS_PER_HOST", "10"),
 		HTTPMaxConnsPerHost:     getEnv("HTTP_MAX_CONNS_PER_HOST", "100"),
 
+		// Remote fetching
+		RemoteFetchTimeout:      getEnv("REMOTE_FETCH_TIMEOUT", "10s"),
+		RemoteFetchMaxBytes:     getEnv("REMOTE_FETCH_MAX_BYTES", "10485760"),
+		RemoteFetchMaxRedirects: getEnv("REMOTE_FETCH_MAX_REDIRECTS", "3"),
+
+		// Object storage
+		StorageBackend:        getEnv("STORAGE_BACKEND", "s3"),
+		PublicBaseURL:         getEnv("PUBLIC_BASE_URL", "http://localhost:8080"),
+		SupabaseStorageBucket: getEnv("SUPABASE_STORAGE_BUCKET", ""),
+		LocalStorageDir:       getEnv("LOCAL_STORAGE_DIR", "./data/assets"),
+		StoragePrivate:        getEnv("STORAGE_PRIVATE", "false") == "true",
+		StorageCDNBaseURL:     getEnv("STORAGE_CDN_BASE_URL", ""),
+		StorageSignedURLTTL:   getEnv("STORAGE_SIGNED_URL_TTL", "1h"),
+
+		// Asset garbage collection
+		AssetGCGracePeriod:      getEnv("ASSET_GC_GRACE_PERIOD", "168h"),
+		AssetGCMode:             getEnv("ASSET_GC_MODE", "quarantine"),
+		AssetGCQuarantinePrefix: getEnv("ASSET_GC_QUARANTINE_PREFIX", "quarantine/"),
+
 		// AWS S3
 		AWSRegion:          getEnv("AWS_REGION", "us-east-1"),
 		AWSAccessKeyID:     getEnv("AWS_ACCESS_KEY_ID", ""),
 		AWSSecretAccessKey: getEnv("AWS_SECRET_ACCESS_KEY", ""),
 		S3BucketName:       getEnv("S3_BUCKET_NAME", ""),
+		S3Endpoint:         getEnv("S3_ENDPOINT", ""),
+		S3ForcePathStyle:   getEnv("S3_FORCE_PATH_STYLE", "false") == "true",
+
+		// Screenshots
+		ScreenshotProvider:           getEnv("SCREENSHOT_PROVIDER", "screenshotone"),
+		ScreenshotOneAPIKey:          getEnv("SCREENSHOTONE_API_KEY", ""),
+		ChromePath:                   getEnv("CHROME_PATH", "chromium-browser"),
+		ScreenshotViewportWidth:      getEnv("SCREENSHOT_VIEWPORT_WIDTH", "1280"),
+		ScreenshotViewportHeight:     getEnv("SCREENSHOT_VIEWPORT_HEIGHT", "720"),
+		ScreenshotDeviceScaleFactor:  getEnv("SCREENSHOT_DEVICE_SCALE_FACTOR", "2"),
+		ScreenshotFormat:             getEnv("SCREENSHOT_FORMAT", "png"),
+		ScreenshotDelay:              getEnv("SCREENSHOT_DELAY", "0s"),
+		ScreenshotHideSelectors:      getEnv("SCREENSHOT_HIDE_SELECTORS", ""),
+		ScreenshotBlockCookieBanners: getEnv("SCREENSHOT_BLOCK_COOKIE_BANNERS", "true"),
+		ScreenshotBadTemplatesDir:    getEnv("SCREENSHOT_BAD_TEMPLATES_DIR", ""),
+		ScreenshotBadTemplateHashes:  getEnv("SCREENSHOT_BAD_TEMPLATE_HASHES", ""),
 
-		// ScreenshotOne API
-		ScreenshotOneAPIKey: getEnv("SCREENSHOTONE_API_KEY", ""),
+		// Image variants
+		ImageVariantWidths: getEnv("IMAGE_VARIANT_WIDTHS", "320,640,1280"),
+		CWebPPath:          getEnv("CWEBP_PATH", "cwebp"),
 
 		// Instagram API
 		IGUserID:         getEnv("IG_USER_ID", ""),
 		IGAccessToken:    getEnv("IG_ACCESS_TOKEN", ""),
 		IGAPIVersion:     getEnv("IG_API_VERSION", "v23.0"),
 		IGPostingEnabled: getEnv("IG_POSTING_ENABLED", "true") == "true",
+		IGPostFormat:     getEnv("IG_POST_FORMAT", "carousel"),
+		IGStoryEnabled:   getEnv("IG_STORY_ENABLED", "false") == "true",
+
+		// Instagram token refresh
+		IGAppID:              getEnv("IG_APP_ID", ""),
+		IGAppSecret:          getEnv("IG_APP_SECRET", ""),
+		IGTokenRefreshBefore: getEnv("IG_TOKEN_REFRESH_BEFORE", "240h"),
+		IGTokenCheckInterval: getEnv("IG_TOKEN_CHECK_INTERVAL", "12h"),
+
+		// Instagram container polling
+		IGPollInitialDelay:     getEnv("IG_POLL_INITIAL_DELAY", "2s"),
+		IGPollMaxDelay:         getEnv("IG_POLL_MAX_DELAY", "10s"),
+		IGPollMaxAttempts:      getEnv("IG_POLL_MAX_ATTEMPTS", "15"),
+		IGVideoPollMaxAttempts: getEnv("IG_VIDEO_POLL_MAX_ATTEMPTS", "40"),
+
+		// Instagram insights
+		IGInsightsEnabled:  getEnv("IG_INSIGHTS_ENABLED", "true") == "true",
+		IGInsightsInterval: getEnv("IG_INSIGHTS_INTERVAL", "6h"),
+		IGInsightsMaxAge:   getEnv("IG_INSIGHTS_MAX_AGE", "720h"),
+
+		// Instagram captions
+		IGCaptionTemplatesDir:  getEnv("IG_CAPTION_TEMPLATES_DIR", ""),
+		IGCaptionHashtags:      getEnv("IG_CAPTION_HASHTAGS", "#startupdose,#startups,#tech,#innovation"),
+		IGCaptionMaxHashtags:   getEnv("IG_CAPTION_MAX_HASHTAGS", "30"),
+		IGHashtagsFirstComment: getEnv("IG_HASHTAGS_FIRST_COMMENT", "false") == "true",
+
+		// Notifications
+		NotifyWebhookURL: getEnv("NOTIFY_WEBHOOK_URL", ""),
 	}
 
 	// Validate required Supabase credentials
@@ -0,0 +1,100 @@
+package database
+
+import (
+	"fmt"
+
+	"startupdose.com/cmd/server/models"
+)
+
+// AssetRepository handles assets database operations
+type AssetRepository struct{}
+
+// NewAssetRepository creates a new AssetRepository instance
+func NewAssetRepository() *AssetRepository {
+	return &AssetRepository{}
+}
+
+// LinkMany records the assets used by a company
+// Re-linking the same hash to the same company is a no-op
+func (r *AssetRepository) LinkMany(assets []models.Asset) error {
+	if len(assets) == 0 {
+		return nil
+	}
+
+	client := GetClient()
+	if client == nil {
+		return fmt.Errorf("database client not initialized")
+	}
+
+	// PostgREST requires every object in a bulk insert to have the same keys,
+	// so missing values are written as NULL rather than left out
+	rows := make([]map[string]interface{}, 0, len(assets))
+	for _, a := range assets {
+		row := map[string]interface{}{
+			"company_id": a.CompanyID,
+			"hash":       a.Hash,
+			"key":        a.Key,
+			"mime_type":  a.MimeType,
+			"size_bytes": a.SizeBytes,
+			"source":     a.Source,
+			"width":      nil,
+			"height":     nil,
+			"source_url": nullIfEmpty(a.SourceURL),
+		}
+		if a.Width > 0 && a.Height > 0 {
+			row["width"] = a.Width
+			row["height"] = a.Height
+		}
+		rows = append(rows, row)
+	}
+
+	_, _, err := client.
+		From("assets").
+		Insert(rows, true, "company_id,hash", "minimal", "").
+		Execute()
+	if err != nil {
+		return fmt.Errorf("failed to insert assets: %w", err)
+	}
+
+	return nil
+}
+
+// assetPageSize is the number of rows fetched per request when scanning all assets
+const assetPageSize = 1000
+
+// ListKeys returns the storage keys of every asset, paging through the table
+// The scan only stops on an empty page: PostgREST may return fewer rows than
+// requested when its max-rows setting is lower than assetPageSize
+func (r *AssetRepository) ListKeys() ([]string, error) {
+	client := GetClient()
+	if client == nil {
+		return nil, fmt.Errorf("database client not initialized")
+	}
+
+	var keys []string
+	for from := 0; ; {
+		var rows []struct {
+			Key string `json:"key"`
+		}
+		_, err := client.
+			From("assets").
+			Select("key", "", false).
+			Order("id", nil).
+			Range(from, from+assetPageSize-1, "").
+			ExecuteTo(&rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query assets: %w", err)
+		}
+
+		if len(rows) == 0 {
+			break
+		}
+		for _, row := range rows {
+			keys = append(keys, row.Key)
+		}
+		// Continue after the rows actually returned
+		from += len(rows)
+	}
+
+	return keys, nil
+}
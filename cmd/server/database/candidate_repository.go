@@ -0,0 +1,57 @@
+package database
+
+import (
+	"fmt"
+
+	"startupdose.com/cmd/server/models"
+)
+
+// CandidateRepository handles company_candidates database operations
+type CandidateRepository struct{}
+
+// NewCandidateRepository creates a new CandidateRepository instance
+func NewCandidateRepository() *CandidateRepository {
+	return &CandidateRepository{}
+}
+
+// InsertMany stores candidates that were generated but not selected
+func (r *CandidateRepository) InsertMany(candidates []models.CompanyCandidate) error {
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	client := GetClient()
+	if client == nil {
+		return fmt.Errorf("database client not initialized")
+	}
+
+	rows := make([]map[string]interface{}, 0, len(candidates))
+	for _, c := range candidates {
+		rows = append(rows, map[string]interface{}{
+			"name":              c.Name,
+			"slug":              c.Slug,
+			"website":           c.Website,
+			"cover_image":       c.CoverImage,
+			"description":       c.Description,
+			"appeal":            c.Appeal,
+			"twitter":           c.Twitter,
+			"linkedin":          c.LinkedIn,
+			"facebook":          c.Facebook,
+			"instagram":         c.Instagram,
+			"score":             c.Score,
+			"checks":            c.Checks,
+			"status":            c.Status,
+			"generation_params": c.Params,
+		})
+	}
+
+	_, _, err := client.
+		From("company_candidates").
+		Insert(rows, false, "", "minimal", "").
+		Execute()
+	if err != nil {
+		return fmt.Errorf("failed to insert candidates: %w", err)
+	}
+
+	return nil
+}
@@ -98,3 +98,192 @@ func (r *CompanyRepository) InsertMap(companyData map[string]interface{}) (*mode
 
 	return &result[0], nil
 }
+
+// Update sets the given columns on a company
+func (r *CompanyRepository) Update(id string, fields map[string]interface{}) error {
+	client := GetClient()
+	if client == nil {
+		return fmt.Errorf("database client not initialized")
+	}
+
+	_, _, err := client.
+		From("companies").
+		Update(fields, "minimal", "").
+		Eq("id", id).
+		Execute()
+	if err != nil {
+		return fmt.Errorf("failed to update company: %w", err)
+	}
+
+	return nil
+}
+
+// ExistsByDomain returns true if a company with the given registrable domain is already stored
+// Slug collisions are not duplicates; slugify.Unique resolves them with a suffix
+func (r *CompanyRepository) ExistsByDomain(domain string) (bool, error) {
+	client := GetClient()
+	if client == nil {
+		return false, fmt.Errorf("database client not initialized")
+	}
+	if domain == "" {
+		return false, nil
+	}
+
+	var companies []struct {
+		ID string `json:"id"`
+	}
+	_, err := client.
+		From("companies").
+		Select("id", "", false).
+		Eq("domain", domain).
+		Limit(1, "").
+		ExecuteTo(&companies)
+	if err != nil {
+		return false, fmt.Errorf("failed to query database: %w", err)
+	}
+
+	return len(companies) > 0, nil
+}
+
+// GetBySlug retrieves a company by its current slug
+// Returns an error containing "company not found" if no company matches
+func (r *CompanyRepository) GetBySlug(slug string) (*models.Company, error) {
+	return r.getOneBy("slug", slug)
+}
+
+// GetByID retrieves a company by its ID
+// Returns an error containing "company not found" if no company matches
+func (r *CompanyRepository) GetByID(id string) (*models.Company, error) {
+	return r.getOneBy("id", id)
+}
+
+// getOneBy retrieves a single company where column equals value
+func (r *CompanyRepository) getOneBy(column, value string) (*models.Company, error) {
+	client := GetClient()
+	if client == nil {
+		return nil, fmt.Errorf("database client not initialized")
+	}
+
+	var companies []models.Company
+	_, err := client.
+		From("companies").
+		Select("*", "", false).
+		Eq(column, value).
+		Limit(1, "").
+		ExecuteTo(&companies)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query database: %w", err)
+	}
+
+	if len(companies) == 0 {
+		return nil, fmt.Errorf("company not found")
+	}
+
+	return &companies[0], nil
+}
+
+// GetByPreviousSlug retrieves a company through the slug history table
+// Returns an error containing "company not found" if the slug was never used
+func (r *CompanyRepository) GetByPreviousSlug(slug string) (*models.Company, error) {
+	client := GetClient()
+	if client == nil {
+		return nil, fmt.Errorf("database client not initialized")
+	}
+
+	var history []struct {
+		CompanyID string `json:"company_id"`
+	}
+	_, err := client.
+		From("company_slug_history").
+		Select("company_id", "", false).
+		Eq("slug", slug).
+		Limit(1, "").
+		ExecuteTo(&history)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query slug history: %w", err)
+	}
+
+	if len(history) == 0 {
+		return nil, fmt.Errorf("company not found")
+	}
+
+	return r.GetByID(history[0].CompanyID)
+}
+
+// SlugTaken returns true if the slug is used by a company or appears in the slug history
+func (r *CompanyRepository) SlugTaken(slug string) (bool, error) {
+	client := GetClient()
+	if client == nil {
+		return false, fmt.Errorf("database client not initialized")
+	}
+
+	for _, table := range []string{"companies", "company_slug_history"} {
+		var rows []struct {
+			Slug string `json:"slug"`
+		}
+		_, err := client.
+			From(table).
+			Select("slug", "", false).
+			Eq("slug", slug).
+			Limit(1, "").
+			ExecuteTo(&rows)
+		if err != nil {
+			return false, fmt.Errorf("failed to query %s: %w", table, err)
+		}
+		if len(rows) > 0 {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// companyPageSize is the number of rows fetched per request when scanning all companies
+const companyPageSize = 1000
+
+// ListImageURLs returns every image URL referenced by any company:
+// cover images, their responsive renditions and social cards
+func (r *CompanyRepository) ListImageURLs() ([]string, error) {
+	client := GetClient()
+	if client == nil {
+		return nil, fmt.Errorf("database client not initialized")
+	}
+
+	var urls []string
+	for from := 0; ; {
+		var companies []models.Company
+		_, err := client.
+			From("companies").
+			Select("id,cover_image,cover_images,square_card_image,portrait_card_image", "", false).
+			Order("created_at", &postgrest.OrderOpts{Ascending: true}).
+			// id breaks created_at ties so pages never overlap or skip rows
+			Order("id", &postgrest.OrderOpts{Ascending: true}).
+			Range(from, from+companyPageSize-1, "").
+			ExecuteTo(&companies)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query database: %w", err)
+		}
+
+		for _, c := range companies {
+			if c.CoverImage != "" {
+				urls = append(urls, c.CoverImage)
+			}
+			for _, img := range c.CoverImages {
+				urls = append(urls, img.URL)
+			}
+			for _, card := range []*string{c.SquareCard, c.PortraitCard} {
+				if card != nil && *card != "" {
+					urls = append(urls, *card)
+				}
+			}
+		}
+
+		// Stop only on an empty page; PostgREST's max-rows may shorten pages
+		if len(companies) == 0 {
+			break
+		}
+		from += len(companies)
+	}
+
+	return urls, nil
+}
@@ -0,0 +1,77 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"startupdose.com/cmd/server/models"
+)
+
+// InstagramTokenRepository handles instagram_tokens database operations
+type InstagramTokenRepository struct{}
+
+// NewInstagramTokenRepository creates a new InstagramTokenRepository instance
+func NewInstagramTokenRepository() *InstagramTokenRepository {
+	return &InstagramTokenRepository{}
+}
+
+// Get returns the stored token for an Instagram account, or nil if none is stored
+func (r *InstagramTokenRepository) Get(igUserID string) (*models.InstagramToken, error) {
+	client := GetClient()
+	if client == nil {
+		return nil, fmt.Errorf("database client not initialized")
+	}
+
+	var tokens []models.InstagramToken
+	_, err := client.
+		From("instagram_tokens").
+		Select("*", "", false).
+		Eq("ig_user_id", igUserID).
+		Limit(1, "").
+		ExecuteTo(&tokens)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query instagram token: %w", err)
+	}
+
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+
+	return &tokens[0], nil
+}
+
+// Save inserts or replaces the stored token for the token's account
+func (r *InstagramTokenRepository) Save(token *models.InstagramToken) error {
+	client := GetClient()
+	if client == nil {
+		return fmt.Errorf("database client not initialized")
+	}
+
+	scopes := token.Scopes
+	if scopes == nil {
+		scopes = []string{}
+	}
+
+	row := map[string]interface{}{
+		"ig_user_id":             token.IGUserID,
+		"access_token":           token.AccessToken,
+		"expires_at":             token.ExpiresAt,
+		"data_access_expires_at": token.DataAccessExpiresAt,
+		"scopes":                 scopes,
+		"is_valid":               token.IsValid,
+		"last_refreshed_at":      token.LastRefreshedAt,
+		"last_checked_at":        token.LastCheckedAt,
+		"last_error":             token.LastError,
+		"updated_at":             time.Now().UTC(),
+	}
+
+	_, _, err := client.
+		From("instagram_tokens").
+		Insert(row, true, "ig_user_id", "minimal", "").
+		Execute()
+	if err != nil {
+		return fmt.Errorf("failed to save instagram token: %w", err)
+	}
+
+	return nil
+}
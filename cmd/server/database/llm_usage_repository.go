@@ -0,0 +1,77 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"startupdose.com/cmd/server/models"
+)
+
+// LLMUsageRepository handles llm_usage database operations
+type LLMUsageRepository struct{}
+
+// NewLLMUsageRepository creates a new LLMUsageRepository instance
+func NewLLMUsageRepository() *LLMUsageRepository {
+	return &LLMUsageRepository{}
+}
+
+// Insert records a single LLM call
+func (r *LLMUsageRepository) Insert(usage *models.LLMUsage) error {
+	client := GetClient()
+	if client == nil {
+		return fmt.Errorf("database client not initialized")
+	}
+
+	row := map[string]interface{}{
+		"model":             usage.Model,
+		"purpose":           usage.Purpose,
+		"prompt_tokens":     usage.PromptTokens,
+		"completion_tokens": usage.CompletionTokens,
+		"total_tokens":      usage.TotalTokens,
+		"cost_usd":          usage.CostUSD,
+	}
+
+	_, _, err := client.
+		From("llm_usage").
+		Insert(row, false, "", "minimal", "").
+		Execute()
+	if err != nil {
+		return fmt.Errorf("failed to insert llm usage: %w", err)
+	}
+
+	return nil
+}
+
+// SumCostSince returns the total cost in USD of calls made at or after since
+// The sum is computed by the llm_usage_cost_since function, so it isn't
+// limited by the number of rows PostgREST returns
+func (r *LLMUsageRepository) SumCostSince(since time.Time) (float64, error) {
+	client := GetClient()
+	if client == nil {
+		return 0, fmt.Errorf("database client not initialized")
+	}
+
+	// Query: SELECT llm_usage_cost_since(since)
+	body := client.Rpc("llm_usage_cost_since", "", map[string]interface{}{
+		"since": since.UTC().Format(time.RFC3339),
+	})
+	if body == "" {
+		return 0, fmt.Errorf("failed to query llm usage: no response from llm_usage_cost_since")
+	}
+
+	var total float64
+	if err := json.Unmarshal([]byte(body), &total); err != nil {
+		// Errors come back as a PostgREST error object instead of a number
+		var apiErr struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		}
+		if json.Unmarshal([]byte(body), &apiErr) == nil && apiErr.Message != "" {
+			return 0, fmt.Errorf("failed to query llm usage: (%s) %s", apiErr.Code, apiErr.Message)
+		}
+		return 0, fmt.Errorf("failed to parse llm usage total %q: %w", body, err)
+	}
+
+	return total, nil
+}
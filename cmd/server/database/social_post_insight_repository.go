@@ -0,0 +1,99 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/supabase-community/postgrest-go"
+	"startupdose.com/cmd/server/models"
+)
+
+// LeaderboardMetrics are the columns the leaderboard can be ranked by
+var LeaderboardMetrics = []string{"engagement", "reach", "impressions", "likes", "comments", "saves"}
+
+// SocialPostInsightRepository handles social_post_insights database operations
+type SocialPostInsightRepository struct{}
+
+// NewSocialPostInsightRepository creates a new SocialPostInsightRepository instance
+func NewSocialPostInsightRepository() *SocialPostInsightRepository {
+	return &SocialPostInsightRepository{}
+}
+
+// Insert appends a metrics snapshot
+func (r *SocialPostInsightRepository) Insert(insight *models.SocialPostInsight) error {
+	client := GetClient()
+	if client == nil {
+		return fmt.Errorf("database client not initialized")
+	}
+
+	row := map[string]interface{}{
+		"social_post_id": insight.SocialPostID,
+		"company_id":     insight.CompanyID,
+		"media_id":       insight.MediaID,
+		"reach":          insight.Reach,
+		"impressions":    insight.Impressions,
+		"likes":          insight.Likes,
+		"comments":       insight.Comments,
+		"saves":          insight.Saves,
+		"fetched_at":     insight.FetchedAt.UTC(),
+	}
+
+	_, _, err := client.
+		From("social_post_insights").
+		Insert(row, false, "", "minimal", "").
+		Execute()
+	if err != nil {
+		return fmt.Errorf("failed to insert social post insights: %w", err)
+	}
+
+	return nil
+}
+
+// ListByCompany returns every snapshot for a company's posts, oldest first
+func (r *SocialPostInsightRepository) ListByCompany(companyID string) ([]models.SocialPostInsight, error) {
+	client := GetClient()
+	if client == nil {
+		return nil, fmt.Errorf("database client not initialized")
+	}
+
+	var insights []models.SocialPostInsight
+	_, err := client.
+		From("social_post_insights").
+		Select("*", "", false).
+		Eq("company_id", companyID).
+		Order("fetched_at", &postgrest.OrderOpts{Ascending: true}).
+		ExecuteTo(&insights)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query social post insights: %w", err)
+	}
+
+	return insights, nil
+}
+
+// Leaderboard returns the posts with the highest latest value of metric,
+// limited to posts published at or after since when since is non-zero
+// metric must be one of LeaderboardMetrics
+func (r *SocialPostInsightRepository) Leaderboard(metric string, since time.Time, limit int) ([]models.LeaderboardEntry, error) {
+	client := GetClient()
+	if client == nil {
+		return nil, fmt.Errorf("database client not initialized")
+	}
+
+	query := client.
+		From("social_post_latest_insights").
+		Select("*", "", false)
+	if !since.IsZero() {
+		query = query.Gte("posted_at", since.UTC().Format(time.RFC3339))
+	}
+
+	var entries []models.LeaderboardEntry
+	_, err := query.
+		Order(metric, &postgrest.OrderOpts{Ascending: false}).
+		Limit(limit, "").
+		ExecuteTo(&entries)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query leaderboard: %w", err)
+	}
+
+	return entries, nil
+}
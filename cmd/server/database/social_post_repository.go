@@ -0,0 +1,241 @@
+package database
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/supabase-community/postgrest-go"
+	"startupdose.com/cmd/server/models"
+)
+
+// uniqueViolation is the PostgreSQL error code postgrest reports as "(23505) ..."
+const uniqueViolation = "(23505)"
+
+// SocialPostRepository handles social_posts database operations
+type SocialPostRepository struct{}
+
+// NewSocialPostRepository creates a new SocialPostRepository instance
+func NewSocialPostRepository() *SocialPostRepository {
+	return &SocialPostRepository{}
+}
+
+// Claim reserves publishing a company to a channel by inserting its row
+// Returns the new row and true, or the existing row and false when another
+// request already claimed the channel; only the claimant may publish
+func (r *SocialPostRepository) Claim(companyID, channel string) (*models.SocialPost, bool, error) {
+	client := GetClient()
+	if client == nil {
+		return nil, false, fmt.Errorf("database client not initialized")
+	}
+
+	row := map[string]interface{}{
+		"company_id": companyID,
+		"channel":    channel,
+		"status":     models.SocialPostStatusPosting,
+		"attempts":   1,
+		"claimed_by": claimant(),
+		"claimed_at": time.Now().UTC(),
+	}
+
+	var posts []models.SocialPost
+	_, err := client.
+		From("social_posts").
+		Insert(row, false, "", "", "").
+		ExecuteTo(&posts)
+	if err != nil {
+		if !strings.HasPrefix(err.Error(), uniqueViolation) {
+			return nil, false, fmt.Errorf("failed to claim social post: %w", err)
+		}
+		existing, getErr := r.Get(companyID, channel)
+		if getErr != nil {
+			return nil, false, getErr
+		}
+		if existing == nil {
+			return nil, false, fmt.Errorf("social post claimed concurrently but not found")
+		}
+		return existing, false, nil
+	}
+
+	if len(posts) == 0 {
+		return nil, false, fmt.Errorf("insert succeeded but no social post was returned")
+	}
+	return &posts[0], true, nil
+}
+
+// Reclaim claims an already recorded post for another attempt, incrementing
+// its attempt count. The update only applies while the row still has the
+// status and attempt count the caller observed, so of two concurrent retries
+// only one wins. Returns the updated row and true, or the current row and
+// false when the row changed in the meantime.
+func (r *SocialPostRepository) Reclaim(post *models.SocialPost) (*models.SocialPost, bool, error) {
+	client := GetClient()
+	if client == nil {
+		return nil, false, fmt.Errorf("database client not initialized")
+	}
+
+	now := time.Now().UTC()
+	row := map[string]interface{}{
+		"status":     models.SocialPostStatusPosting,
+		"attempts":   post.Attempts + 1,
+		"claimed_by": claimant(),
+		"claimed_at": now,
+		"error":      nil,
+		"updated_at": now,
+	}
+
+	var posts []models.SocialPost
+	_, err := client.
+		From("social_posts").
+		Update(row, "", "").
+		Eq("id", post.ID).
+		Eq("status", post.Status).
+		Eq("attempts", strconv.Itoa(post.Attempts)).
+		ExecuteTo(&posts)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to reclaim social post: %w", err)
+	}
+
+	if len(posts) == 0 {
+		current, err := r.Get(post.CompanyID, post.Channel)
+		if err != nil {
+			return nil, false, err
+		}
+		if current == nil {
+			return nil, false, fmt.Errorf("social post not found")
+		}
+		return current, false, nil
+	}
+	return &posts[0], true, nil
+}
+
+// Get returns the post for a company and channel, or nil if none exists
+func (r *SocialPostRepository) Get(companyID, channel string) (*models.SocialPost, error) {
+	client := GetClient()
+	if client == nil {
+		return nil, fmt.Errorf("database client not initialized")
+	}
+
+	var posts []models.SocialPost
+	_, err := client.
+		From("social_posts").
+		Select("*", "", false).
+		Eq("company_id", companyID).
+		Eq("channel", channel).
+		Limit(1, "").
+		ExecuteTo(&posts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query social post: %w", err)
+	}
+
+	if len(posts) == 0 {
+		return nil, nil
+	}
+	return &posts[0], nil
+}
+
+// ListByCompany returns every post recorded for a company
+func (r *SocialPostRepository) ListByCompany(companyID string) ([]models.SocialPost, error) {
+	client := GetClient()
+	if client == nil {
+		return nil, fmt.Errorf("database client not initialized")
+	}
+
+	var posts []models.SocialPost
+	_, err := client.
+		From("social_posts").
+		Select("*", "", false).
+		Eq("company_id", companyID).
+		Order("created_at", &postgrest.OrderOpts{Ascending: true}).
+		ExecuteTo(&posts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query social posts: %w", err)
+	}
+
+	return posts, nil
+}
+
+// ListPostedSince returns posts on a channel published at or after since
+// Only posts with a media ID are returned
+func (r *SocialPostRepository) ListPostedSince(channel string, since time.Time) ([]models.SocialPost, error) {
+	client := GetClient()
+	if client == nil {
+		return nil, fmt.Errorf("database client not initialized")
+	}
+
+	var posts []models.SocialPost
+	_, err := client.
+		From("social_posts").
+		Select("*", "", false).
+		Eq("channel", channel).
+		Eq("status", models.SocialPostStatusPosted).
+		Not("media_id", "is", "null").
+		Gte("posted_at", since.UTC().Format(time.RFC3339)).
+		Order("posted_at", &postgrest.OrderOpts{Ascending: false}).
+		ExecuteTo(&posts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query social posts: %w", err)
+	}
+
+	return posts, nil
+}
+
+// Complete records the outcome of the claimed attempt
+// mediaID, containerID and permalink may be empty; errMsg is stored for failures
+func (r *SocialPostRepository) Complete(id string, posted bool, containerID, mediaID, permalink, errMsg string) error {
+	client := GetClient()
+	if client == nil {
+		return fmt.Errorf("database client not initialized")
+	}
+
+	now := time.Now().UTC()
+	row := map[string]interface{}{
+		"status":     models.SocialPostStatusFailed,
+		"error":      nullIfEmpty(errMsg),
+		"updated_at": now,
+	}
+	if posted {
+		row["status"] = models.SocialPostStatusPosted
+		row["posted_at"] = now
+		row["error"] = nil
+	}
+	if containerID != "" {
+		row["container_id"] = containerID
+	}
+	if mediaID != "" {
+		row["media_id"] = mediaID
+	}
+	if permalink != "" {
+		row["permalink"] = permalink
+	}
+
+	_, _, err := client.
+		From("social_posts").
+		Update(row, "minimal", "").
+		Eq("id", id).
+		Execute()
+	if err != nil {
+		return fmt.Errorf("failed to update social post: %w", err)
+	}
+
+	return nil
+}
+
+// claimant identifies this process in claimed_by
+func claimant() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s/%d", host, os.Getpid())
+}
+
+// nullIfEmpty maps "" to SQL NULL
+func nullIfEmpty(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
@@ -0,0 +1,256 @@
+// Package fetch downloads remote resources from untrusted URLs (AI-supplied
+// websites and images) without exposing internal services.
+//
+// Every connection is checked after DNS resolution, so hostnames resolving to
+// private, loopback or link-local addresses are refused even when they change
+// between lookups. Redirects are re-checked the same way and capped, bodies
+// are size-limited, and content types are sniffed from the data.
+package fetch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// Defaults used when Options leaves a field zero
+const (
+	DefaultTimeout      = 10 * time.Second
+	DefaultMaxBytes     = 10 << 20 // 10 MiB
+	DefaultMaxRedirects = 3
+)
+
+// sniffLen is the number of bytes http.DetectContentType considers
+const sniffLen = 512
+
+var (
+	// ErrBlockedAddress is returned when a URL resolves to a non-public address
+	ErrBlockedAddress = errors.New("destination address not allowed")
+	// ErrTooLarge is returned when a body exceeds the size limit
+	ErrTooLarge = errors.New("response body too large")
+	// ErrTooManyRedirects is returned when a URL redirects too often
+	ErrTooManyRedirects = errors.New("too many redirects")
+)
+
+// blockedPrefixes are ranges not covered by the netip.Addr predicates
+// that must never be reachable from remote fetches
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),      // "this" network
+	netip.MustParsePrefix("100.64.0.0/10"),  // carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),   // IETF protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"),  // benchmarking
+	netip.MustParsePrefix("240.0.0.0/4"),    // reserved, includes broadcast
+	netip.MustParsePrefix("64:ff9b::/96"),   // NAT64, can embed private IPv4
+	netip.MustParsePrefix("64:ff9b:1::/48"), // local-use NAT64
+	netip.MustParsePrefix("2001:db8::/32"),  // documentation
+}
+
+// Options configures a Fetcher
+type Options struct {
+	Timeout      time.Duration
+	MaxBytes     int64
+	MaxRedirects int
+}
+
+// Response is a fetched resource
+type Response struct {
+	// URL is the final URL after redirects
+	URL        string
+	StatusCode int
+	// ContentType is sniffed from the body, not taken from headers
+	ContentType string
+	// HeaderContentType is the Content-Type the server claimed
+	HeaderContentType string
+	Body              []byte
+}
+
+// Fetcher performs hardened GET requests
+type Fetcher struct {
+	client   *http.Client
+	maxBytes int64
+}
+
+// New creates a Fetcher
+func New(opts Options) *Fetcher {
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if opts.MaxBytes <= 0 {
+		opts.MaxBytes = DefaultMaxBytes
+	}
+	if opts.MaxRedirects < 0 {
+		opts.MaxRedirects = 0
+	} else if opts.MaxRedirects == 0 {
+		opts.MaxRedirects = DefaultMaxRedirects
+	}
+
+	dialer := NewDialer()
+
+	maxRedirects := opts.MaxRedirects
+	return &Fetcher{
+		client: &http.Client{
+			Timeout: opts.Timeout,
+			Transport: &http.Transport{
+				// A proxy would connect on our behalf and bypass the address check
+				Proxy:                 nil,
+				DialContext:           dialer.DialContext,
+				TLSHandshakeTimeout:   5 * time.Second,
+				ResponseHeaderTimeout: opts.Timeout,
+				MaxIdleConns:          10,
+				IdleConnTimeout:       30 * time.Second,
+			},
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) > maxRedirects {
+					return ErrTooManyRedirects
+				}
+				return checkURL(req.URL)
+			},
+		},
+		maxBytes: opts.MaxBytes,
+	}
+}
+
+// NewDialer returns a dialer that refuses to connect to non-public addresses,
+// for code that makes its own connections to untrusted hosts
+func NewDialer() *net.Dialer {
+	return &net.Dialer{
+		Timeout:   5 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   checkDialAddress,
+	}
+}
+
+// Get downloads the resource at rawURL
+// Non-200 responses and bodies over the size limit are errors
+func (f *Fetcher) Get(ctx context.Context, rawURL string) (*Response, error) {
+	resp, err := f.do(ctx, rawURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	// Read one byte past the limit to tell "exactly at" from "over"
+	body, err := io.ReadAll(io.LimitReader(resp.Body, f.maxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read body: %w", err)
+	}
+	if int64(len(body)) > f.maxBytes {
+		return nil, fmt.Errorf("%w: limit is %d bytes", ErrTooLarge, f.maxBytes)
+	}
+
+	return newResponse(resp, body), nil
+}
+
+// Probe requests rawURL and reads only enough of the body to sniff its type
+// Any status code is returned without error so callers can judge reachability
+func (f *Fetcher) Probe(ctx context.Context, rawURL string) (*Response, error) {
+	resp, err := f.do(ctx, rawURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	head, err := io.ReadAll(io.LimitReader(resp.Body, sniffLen))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read body: %w", err)
+	}
+
+	return newResponse(resp, head), nil
+}
+
+// do validates rawURL and sends the request
+func (f *Fetcher) do(ctx context.Context, rawURL string) (*http.Response, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+	if err := checkURL(u); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "StartupDoseBot/1.0 (+https://startupdose.com)")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	return resp, nil
+}
+
+// newResponse builds a Response, sniffing the content type from body
+func newResponse(resp *http.Response, body []byte) *Response {
+	return &Response{
+		URL:               resp.Request.URL.String(),
+		StatusCode:        resp.StatusCode,
+		ContentType:       http.DetectContentType(body),
+		HeaderContentType: resp.Header.Get("Content-Type"),
+		Body:              body,
+	}
+}
+
+// checkURL rejects non-HTTP schemes, credentials and literal non-public IPs
+// Hostnames are checked at dial time, after resolution
+func checkURL(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("unsupported URL scheme %q", u.Scheme)
+	}
+	if u.User != nil {
+		return fmt.Errorf("URLs with credentials are not allowed")
+	}
+	if u.Hostname() == "" {
+		return fmt.Errorf("URL has no host")
+	}
+	if addr, err := netip.ParseAddr(u.Hostname()); err == nil && !IsPublicAddr(addr) {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, addr)
+	}
+	return nil
+}
+
+// checkDialAddress runs for every outgoing connection with the resolved address
+func checkDialAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, address)
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil || !IsPublicAddr(addr) {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, host)
+	}
+	return nil
+}
+
+// IsPublicAddr reports whether addr is a globally routable unicast address
+func IsPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() ||
+		addr.IsLoopback() ||
+		addr.IsPrivate() ||
+		addr.IsLinkLocalUnicast() ||
+		addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() ||
+		addr.IsMulticast() ||
+		addr.IsUnspecified() {
+		return false
+	}
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
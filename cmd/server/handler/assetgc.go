@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"startupdose.com/cmd/server/assetgc"
+)
+
+// assetGCOptionsFromEnv reads ASSET_GC_GRACE_PERIOD, ASSET_GC_MODE and ASSET_GC_QUARANTINE_PREFIX
+func assetGCOptionsFromEnv() assetgc.Options {
+	opts := assetgc.Options{
+		GracePeriod:      assetgc.DefaultGracePeriod,
+		QuarantinePrefix: assetgc.DefaultQuarantinePrefix,
+	}
+
+	if v := os.Getenv("ASSET_GC_GRACE_PERIOD"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			opts.GracePeriod = d
+		} else {
+			log.Printf("WARNING: Invalid ASSET_GC_GRACE_PERIOD %q, using default\n", v)
+		}
+	}
+	if v := os.Getenv("ASSET_GC_QUARANTINE_PREFIX"); v != "" {
+		opts.QuarantinePrefix = v
+	}
+	if os.Getenv("ASSET_GC_MODE") == assetgc.ActionDelete {
+		opts.QuarantinePrefix = ""
+	}
+
+	return opts
+}
+
+// AssetGCHandler handles POST /admin/assets/gc
+// Runs orphaned asset collection and returns the report
+// Defaults to a dry run; pass ?dry_run=false to delete or quarantine
+// ?grace_period=72h overrides ASSET_GC_GRACE_PERIOD
+func AssetGCHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	opts := assetGCOptionsFromEnv()
+	opts.DryRun = true
+
+	query := r.URL.Query()
+	if v := query.Get("dry_run"); v != "" {
+		dryRun, err := strconv.ParseBool(v)
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(ErrorResponse{
+				Error:   "bad_request",
+				Message: "dry_run must be true or false",
+			})
+			return
+		}
+		opts.DryRun = dryRun
+	}
+	if v := query.Get("grace_period"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(ErrorResponse{
+				Error:   "bad_request",
+				Message: "grace_period must be a positive duration such as 168h",
+			})
+			return
+		}
+		opts.GracePeriod = d
+	}
+
+	report, err := assetgc.Run(r.Context(), opts)
+	if err != nil {
+		log.Printf("ERROR: Asset garbage collection failed: %v\n", err)
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(ErrorResponse{
+			Error:   "internal_server_error",
+			Message: "Asset garbage collection failed",
+		})
+		return
+	}
+
+	log.Printf("INFO: Asset GC scanned %d objects, %d orphaned, %d removed (dry run: %t)\n",
+		report.Scanned, len(report.Orphans), report.Removed, report.DryRun)
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"context"
+	"log"
+
+	"startupdose.com/cmd/server/database"
+	"startupdose.com/cmd/server/imaging"
+	"startupdose.com/cmd/server/models"
+	"startupdose.com/cmd/server/screenshot"
+	"startupdose.com/cmd/server/storage"
+)
+
+// linkCompanyAssets records the stored objects used by a company in the assets table
+// Failures are logged; the company itself is already saved
+func linkCompanyAssets(companyID string, assets []storage.Asset) {
+	if companyID == "" || len(assets) == 0 {
+		return
+	}
+
+	rows := make([]models.Asset, 0, len(assets))
+	for _, a := range assets {
+		rows = append(rows, models.Asset{
+			CompanyID: companyID,
+			Hash:      a.Hash,
+			Key:       a.Key,
+			MimeType:  a.ContentType,
+			SizeBytes: a.Size,
+			Width:     a.Width,
+			Height:    a.Height,
+			Source:    a.Source,
+			SourceURL: a.SourceURL,
+		})
+	}
+
+	if err := database.NewAssetRepository().LinkMany(rows); err != nil {
+		log.Printf("WARNING: Failed to record company assets: %v\n", err)
+		return
+	}
+	log.Printf("Recorded %d assets for company %s\n", len(rows), companyID)
+}
+
+// companyImages are the stored images rendered from a website screenshot
+type companyImages struct {
+	Screenshot  []byte
+	CoverURL    string
+	CoverImages []models.CoverImage
+	// CardURLs are the social card URLs keyed by format name
+	CardURLs map[string]string
+}
+
+// captureCompanyImages screenshots a website, retrying blank or blocked pages,
+// and stores the screenshot, its responsive variants and the social cards
+// Returns nil when no usable screenshot could be captured and stored
+func captureCompanyImages(ctx context.Context, uploader *storage.Uploader, s screenshot.Screenshotter, websiteURL string, info imaging.CardInfo) *companyImages {
+	screenshotBytes, err := captureUsableScreenshot(ctx, s, websiteURL)
+	if err != nil {
+		log.Printf("ERROR: Failed to capture screenshot: %v\n", err)
+		return nil
+	}
+
+	// Upload screenshot to object storage
+	shot, err := uploader.UploadScreenshot(ctx, screenshotBytes)
+	if err != nil {
+		log.Printf("ERROR: Failed to upload screenshot: %v\n", err)
+		return nil
+	}
+	log.Printf("Successfully captured and uploaded screenshot: %s\n", shot.URL)
+
+	return &companyImages{
+		Screenshot: screenshotBytes,
+		CoverURL:   shot.URL,
+		// Responsive renditions for the frontend's srcset
+		CoverImages: uploadCoverImageVariants(ctx, uploader, screenshotBytes, shot.URL),
+		// Render Instagram-ready cards from the screenshot
+		CardURLs: composeAndUploadCards(ctx, uploader, screenshotBytes, info),
+	}
+}
+
+// resolveCompanyURLs rewrites the stored image URLs of a company into URLs
+// clients can fetch (CDN or presigned, depending on storage configuration)
+func resolveCompanyURLs(ctx context.Context, company *models.Company) {
+	if company == nil {
+		return
+	}
+
+	company.CoverImage = storage.ResolveURL(ctx, company.CoverImage)
+	for i := range company.CoverImages {
+		company.CoverImages[i].URL = storage.ResolveURL(ctx, company.CoverImages[i].URL)
+	}
+	for _, card := range []*string{company.SquareCard, company.PortraitCard} {
+		if card != nil {
+			*card = storage.ResolveURL(ctx, *card)
+		}
+	}
+}
+
+// mirrorCoverImage copies an external cover image into our storage, since
+// AI-supplied URLs often break before Instagram or the frontend fetch them.
+// Returns the stored URL, or "" when the image can't be fetched or isn't a
+// valid image. Without object storage the original URL is kept as-is.
+func mirrorCoverImage(ctx context.Context, uploader *storage.Uploader, imageURL string) string {
+	if imageURL == "" {
+		return ""
+	}
+	if uploader == nil {
+		log.Println("WARNING: Object storage not configured, using original image URL from OpenAI")
+		return imageURL
+	}
+
+	asset, err := uploader.UploadImageFromURL(ctx, imageURL)
+	if err != nil {
+		log.Printf("WARNING: Failed to mirror cover image %q, saving without one: %v\n", imageURL, err)
+		return ""
+	}
+
+	log.Printf("Mirrored cover image %s to %s\n", imageURL, asset.URL)
+	return asset.URL
+}
@@ -0,0 +1,312 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"startupdose.com/cmd/server/database"
+	"startupdose.com/cmd/server/models"
+	"startupdose.com/cmd/server/slugify"
+	"startupdose.com/cmd/server/urlnorm"
+)
+
+// Best-of-N generation defaults
+const (
+	defaultCandidateCount = 3
+	maxCandidateCount     = 5
+	candidateCheckTimeout = 8 * time.Second
+)
+
+// Candidate check names, used as rubric keys and stored with each candidate
+const (
+	checkWebsiteAlive     = "website_alive"
+	checkImageOK          = "image_ok"
+	checkAppealWellFormed = "appeal_well_formed"
+	checkHasSocials       = "has_socials"
+	checkNotDuplicate     = "not_duplicate"
+)
+
+// defaultRubric weights each passed check; not_duplicate is always required
+var defaultRubric = map[string]float64{
+	checkWebsiteAlive:     3,
+	checkImageOK:          2,
+	checkAppealWellFormed: 2,
+	checkHasSocials:       1,
+}
+
+// appealItemPattern matches a single non-empty <li> item
+var appealItemPattern = regexp.MustCompile(`(?s)<li>\s*(.*?)\s*</li>`)
+
+// candidate is a generated company along with its validation results
+type candidate struct {
+	Data    *CompanyFromAI
+	Slug    string
+	Website *urlnorm.Website
+	Checks  map[string]bool
+	Score   float64
+	Err     error
+}
+
+// disqualified returns true if the candidate can never be featured
+func (c *candidate) disqualified() bool {
+	return c.Err != nil || !c.Checks[checkNotDuplicate]
+}
+
+// candidateCount reads GENERATION_CANDIDATES, clamped to [1, maxCandidateCount]
+func candidateCount() int {
+	n, err := strconv.Atoi(os.Getenv("GENERATION_CANDIDATES"))
+	if err != nil || n < 1 {
+		return defaultCandidateCount
+	}
+	if n > maxCandidateCount {
+		return maxCandidateCount
+	}
+	return n
+}
+
+// loadRubric parses CANDIDATE_RUBRIC ("website_alive=3,image_ok=2,...")
+// Unknown or invalid entries are ignored; an empty value uses defaultRubric
+func loadRubric() map[string]float64 {
+	raw := os.Getenv("CANDIDATE_RUBRIC")
+	if raw == "" {
+		return defaultRubric
+	}
+
+	rubric := make(map[string]float64)
+	for _, entry := range strings.Split(raw, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok {
+			log.Printf("WARNING: Ignoring invalid rubric entry %q\n", entry)
+			continue
+		}
+		if _, known := defaultRubric[key]; !known {
+			log.Printf("WARNING: Ignoring unknown rubric check %q\n", key)
+			continue
+		}
+		weight, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			log.Printf("WARNING: Ignoring invalid rubric weight %q\n", entry)
+			continue
+		}
+		rubric[key] = weight
+	}
+	return rubric
+}
+
+// generateBestCandidate asks the LLM for n candidates concurrently, validates
+// and scores each one, and returns the winner along with the remaining candidates
+func generateBestCandidate(ctx context.Context, apiKey, prompt string, n int) (*candidate, []*candidate, error) {
+	rubric := loadRubric()
+	candidates := make([]*candidate, n)
+
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			c := &candidate{Checks: map[string]bool{}}
+			candidates[i] = c
+
+			c.Data, c.Err = generateCompanyFromAI(apiKey, prompt)
+			if c.Err != nil {
+				return
+			}
+			c.Slug = slugify.Make(c.Data.Name)
+			// Without a valid website the candidate can't be deduplicated,
+			// screenshotted or stored consistently
+			website, err := urlnorm.Normalize(c.Data.Website)
+			if err != nil {
+				c.Err = fmt.Errorf("candidate %q has an invalid website: %w", c.Data.Name, err)
+				return
+			}
+			c.Website = website
+			// Keep the host as given for fetching and linking; only the stored
+			// canonical form drops "www."
+			c.Data.Website = website.Link
+			validateCandidate(ctx, c)
+			c.Score = scoreCandidate(c, rubric)
+		}(i)
+	}
+	wg.Wait()
+
+	// Drop repeated picks; the LLM often returns the same startup more than once
+	seen := make(map[string]bool)
+	var best *candidate
+	var rest []*candidate
+	var lastErr error
+	for _, c := range candidates {
+		if c.Err != nil {
+			log.Printf("WARNING: Candidate generation failed: %v\n", c.Err)
+			lastErr = c.Err
+			continue
+		}
+		// Keyed by domain so different startups with similar names are all kept
+		key := c.Slug
+		if c.Website != nil && c.Website.Domain != "" {
+			key = c.Website.Domain
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		log.Printf("Candidate %q scored %.2f (checks: %v)\n", c.Data.Name, c.Score, c.Checks)
+		if !c.disqualified() && (best == nil || c.Score > best.Score) {
+			if best != nil {
+				rest = append(rest, best)
+			}
+			best = c
+			continue
+		}
+		rest = append(rest, c)
+	}
+
+	if best == nil {
+		if lastErr != nil && len(rest) == 0 {
+			return nil, nil, lastErr
+		}
+		return nil, rest, fmt.Errorf("no usable candidate among %d generated (all duplicates or failed)", n)
+	}
+
+	return best, rest, nil
+}
+
+// validateCandidate runs the checks used for scoring
+func validateCandidate(ctx context.Context, c *candidate) {
+	ctx, cancel := context.WithTimeout(ctx, candidateCheckTimeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	var websiteAlive, imageOK bool
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		websiteAlive = c.Website != nil && isURLReachable(ctx, c.Website.Link, "")
+	}()
+	go func() {
+		defer wg.Done()
+		imageOK = isURLReachable(ctx, c.Data.CoverImage, "image/")
+	}()
+	wg.Wait()
+
+	c.Checks[checkWebsiteAlive] = websiteAlive
+	c.Checks[checkImageOK] = imageOK
+	c.Checks[checkAppealWellFormed] = isAppealWellFormed(c.Data.Appeal)
+	c.Checks[checkHasSocials] = c.Data.LinkedIn != "" || c.Data.Twitter != "" ||
+		c.Data.Instagram != "" || c.Data.Facebook != ""
+
+	var domain string
+	if c.Website != nil {
+		domain = c.Website.Domain
+	}
+	repo := database.NewCompanyRepository()
+	exists, err := repo.ExistsByDomain(domain)
+	if err != nil {
+		// Don't disqualify candidates when the database can't be reached
+		log.Printf("WARNING: Duplicate check failed for %q: %v\n", c.Data.Name, err)
+	}
+	c.Checks[checkNotDuplicate] = !exists
+}
+
+// scoreCandidate sums the rubric weight of each passed check
+func scoreCandidate(c *candidate, rubric map[string]float64) float64 {
+	var score float64
+	for check, weight := range rubric {
+		if c.Checks[check] {
+			score += weight
+		}
+	}
+	return score
+}
+
+// isURLReachable returns true if a GET to the URL succeeds with a non-error status
+// When contentTypePrefix is set, the sniffed content type must also match it
+func isURLReachable(ctx context.Context, rawURL, contentTypePrefix string) bool {
+	if rawURL == "" {
+		return false
+	}
+
+	resp, err := remoteFetcher().Probe(ctx, rawURL)
+	if err != nil {
+		return false
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return false
+	}
+	if contentTypePrefix != "" && !strings.HasPrefix(resp.ContentType, contentTypePrefix) {
+		return false
+	}
+	return true
+}
+
+// isAppealWellFormed checks that the appeal is exactly five non-empty <li> items
+func isAppealWellFormed(appeal string) bool {
+	if strings.Contains(appeal, "<ul") {
+		return false
+	}
+	items := appealItemPattern.FindAllStringSubmatch(appeal, -1)
+	if len(items) != 5 {
+		return false
+	}
+	for _, item := range items {
+		if strings.TrimSpace(item[1]) == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// saveCandidateBacklog stores candidates that were not selected
+// Failures are logged but never fail the request
+func saveCandidateBacklog(candidates []*candidate, genReq *GenerateCompanyRequest) {
+	var params map[string]interface{}
+	if genReq != nil && !genReq.IsEmpty() {
+		params = map[string]interface{}{
+			"category": genReq.Category,
+			"region":   genReq.Region,
+			"stage":    genReq.Stage,
+			"exclude":  genReq.Exclude,
+			"theme":    genReq.Theme,
+		}
+	}
+
+	rows := make([]models.CompanyCandidate, 0, len(candidates))
+	for _, c := range candidates {
+		if c.Err != nil || c.Data == nil {
+			continue
+		}
+		status := models.CandidateStatusBacklog
+		if !c.Checks[checkNotDuplicate] {
+			status = models.CandidateStatusDuplicate
+		}
+		rows = append(rows, models.CompanyCandidate{
+			Name:        c.Data.Name,
+			Slug:        c.Slug,
+			Website:     c.Data.Website,
+			CoverImage:  c.Data.CoverImage,
+			Description: c.Data.Description,
+			Appeal:      c.Data.Appeal,
+			Twitter:     c.Data.Twitter,
+			LinkedIn:    c.Data.LinkedIn,
+			Facebook:    c.Data.Facebook,
+			Instagram:   c.Data.Instagram,
+			Score:       c.Score,
+			Checks:      c.Checks,
+			Status:      status,
+			Params:      params,
+		})
+	}
+
+	if err := database.NewCandidateRepository().InsertMany(rows); err != nil {
+		log.Printf("ERROR: Failed to save candidate backlog: %v\n", err)
+	}
+}
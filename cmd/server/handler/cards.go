@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"context"
+	"html"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+
+	"startupdose.com/cmd/server/imaging"
+	"startupdose.com/cmd/server/storage"
+)
+
+// Instagram post formats selected by IG_POST_FORMAT
+const (
+	igPostFormatSingle   = "single"
+	igPostFormatCarousel = "carousel"
+)
+
+// Carousels hold at most 10 slides; the cover and call-to-action cards take two
+const maxCarouselBullets = 8
+
+// igPostFormat returns the configured Instagram post format, defaulting to carousel
+func igPostFormat() string {
+	if os.Getenv("IG_POST_FORMAT") == igPostFormatSingle {
+		return igPostFormatSingle
+	}
+	return igPostFormatCarousel
+}
+
+// igStoryEnabled reports whether a story is posted alongside the feed post
+func igStoryEnabled() bool {
+	return os.Getenv("IG_STORY_ENABLED") == "true"
+}
+
+// cardColumns maps a card format to the companies column storing its URL
+var cardColumns = map[string]string{
+	imaging.CardSquare.Name:   "square_card_image",
+	imaging.CardPortrait.Name: "portrait_card_image",
+}
+
+// composeAndUploadCards renders the branded social cards from a screenshot and
+// uploads them next to the original. Returns card URLs keyed by format name;
+// formats that fail are logged and skipped.
+func composeAndUploadCards(ctx context.Context, uploader *storage.Uploader, screenshotBytes []byte, info imaging.CardInfo) map[string]string {
+	urls := make(map[string]string)
+	for _, format := range imaging.CardFormats {
+		card, err := imaging.ComposeCard(screenshotBytes, info, format)
+		if err != nil {
+			log.Printf("ERROR: Failed to compose %s card: %v\n", format.Name, err)
+			continue
+		}
+
+		asset, err := uploader.UploadCard(ctx, card)
+		if err != nil {
+			log.Printf("ERROR: Failed to upload %s card: %v\n", format.Name, err)
+			continue
+		}
+
+		log.Printf("Successfully composed and uploaded %s card: %s\n", format.Name, asset.URL)
+		urls[format.Name] = asset.URL
+	}
+	return urls
+}
+
+// composeAndUploadStoryCard renders the full-screen story card from a screenshot
+// and uploads it. Returns its URL, or "" on failure.
+func composeAndUploadStoryCard(ctx context.Context, uploader *storage.Uploader, screenshotBytes []byte, info imaging.CardInfo) string {
+	card, err := imaging.ComposeCard(screenshotBytes, info, imaging.CardStory)
+	if err != nil {
+		log.Printf("ERROR: Failed to compose story card: %v\n", err)
+		return ""
+	}
+
+	asset, err := uploader.UploadCard(ctx, card)
+	if err != nil {
+		log.Printf("ERROR: Failed to upload story card: %v\n", err)
+		return ""
+	}
+
+	log.Printf("Successfully composed and uploaded story card: %s\n", asset.URL)
+	return asset.URL
+}
+
+// htmlTagPattern matches inline markup the model sometimes adds inside appeal items
+var htmlTagPattern = regexp.MustCompile(`<[^>]+>`)
+
+// appealBullets extracts the plain text of each <li> item in an appeal
+func appealBullets(appeal string) []string {
+	var bullets []string
+	for _, item := range appealItemPattern.FindAllStringSubmatch(appeal, -1) {
+		text := html.UnescapeString(htmlTagPattern.ReplaceAllString(item[1], ""))
+		text = strings.Join(strings.Fields(text), " ")
+		if text != "" {
+			bullets = append(bullets, text)
+		}
+	}
+	return bullets
+}
+
+// buildCarousel renders the carousel slides when IG_POST_FORMAT selects
+// carousels: the portrait card followed by one card per appeal bullet
+// Returns nil when carousels are disabled or the slides can't be built
+func buildCarousel(ctx context.Context, uploader *storage.Uploader, cardURLs map[string]string, appeal string, info imaging.CardInfo) []string {
+	portraitURL, ok := cardURLs[imaging.CardPortrait.Name]
+	bullets := appealBullets(appeal)
+	if igPostFormat() != igPostFormatCarousel || !ok || len(bullets) == 0 {
+		return nil
+	}
+	if len(bullets) > maxCarouselBullets {
+		bullets = bullets[:maxCarouselBullets]
+	}
+	return composeAndUploadCarousel(ctx, uploader, portraitURL, bullets, info)
+}
+
+// composeAndUploadCarousel renders and uploads the Instagram carousel slides:
+// the portrait card framing the screenshot, one card per appeal bullet and a
+// closing call-to-action card. Returns the slide URLs in order, or nil if any
+// slide fails, since a carousel with gaps would read out of sequence.
+func composeAndUploadCarousel(ctx context.Context, uploader *storage.Uploader, coverCardURL string, bullets []string, info imaging.CardInfo) []string {
+	urls := []string{coverCardURL}
+
+	slides := make([][]byte, 0, len(bullets)+1)
+	for i, bullet := range bullets {
+		card, err := imaging.ComposeBulletCard(bullet, i+1, len(bullets), info, imaging.CardPortrait)
+		if err != nil {
+			log.Printf("ERROR: Failed to compose carousel card %d: %v\n", i+1, err)
+			return nil
+		}
+		slides = append(slides, card)
+	}
+	cta, err := imaging.ComposeCTACard(info, imaging.CardPortrait)
+	if err != nil {
+		log.Printf("ERROR: Failed to compose carousel call-to-action card: %v\n", err)
+		return nil
+	}
+	slides = append(slides, cta)
+
+	for i, slide := range slides {
+		asset, err := uploader.UploadCard(ctx, slide)
+		if err != nil {
+			log.Printf("ERROR: Failed to upload carousel card %d: %v\n", i+1, err)
+			return nil
+		}
+		urls = append(urls, asset.URL)
+	}
+
+	log.Printf("Successfully composed and uploaded %d carousel cards\n", len(slides))
+	return urls
+}
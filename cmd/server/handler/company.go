@@ -6,17 +6,21 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
-	"regexp"
 	"strings"
 	"time"
 
+	"startupdose.com/cmd/server/budget"
 	"startupdose.com/cmd/server/database"
+	"startupdose.com/cmd/server/igtoken"
+	"startupdose.com/cmd/server/imaging"
 	"startupdose.com/cmd/server/instagram"
 	"startupdose.com/cmd/server/models"
+	"startupdose.com/cmd/server/slugify"
 	"startupdose.com/cmd/server/storage"
 )
 
@@ -83,6 +87,12 @@ Return your answer as a SINGLE JSON object with the following fields:
 <li>Reason 5…</li>"
 
 * Each bullet should be specific and compelling (traction, innovation, niche, team, product quality, etc.), written in a tone suitable for social media.
+* "category": string
+
+  The startup's main category in one or two lowercase words, e.g. "fintech", "dev tools", "climate tech".
+* "keywords": array of strings
+
+  Three to six short lowercase keywords describing the product and its market, e.g. ["payments", "small business", "invoicing"]. These are turned into hashtags.
 * "linkedin": string
 
   The company's LinkedIn page URL IF you are reasonably confident it exists and you know it.
@@ -126,30 +136,76 @@ type OpenAIChatRequest struct {
 }
 
 type OpenAIChatResponse struct {
+	Model   string `json:"model"`
 	Choices []struct {
 		Message OpenAIMessage `json:"message"`
 	} `json:"choices"`
+	Usage OpenAIUsage `json:"usage"`
+}
+
+// OpenAIUsage holds the token counts reported for a chat completion
+type OpenAIUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
 }
 
 // CompanyFromAI represents the company data structure returned by OpenAI
 type CompanyFromAI struct {
-	Name        string `json:"name"`
-	Website     string `json:"website"`
-	CoverImage  string `json:"cover_image"`
-	Description string `json:"description"`
-	Appeal      string `json:"appeal"`
-	LinkedIn    string `json:"linkedin"`
-	Instagram   string `json:"instagram"`
-	Facebook    string `json:"facebook"`
-	Twitter     string `json:"twitter"`
+	Name        string      `json:"name"`
+	Website     string      `json:"website"`
+	CoverImage  string      `json:"cover_image"`
+	Description string      `json:"description"`
+	Appeal      string      `json:"appeal"`
+	Category    string      `json:"category"`
+	Keywords    keywordList `json:"keywords"`
+	LinkedIn    string      `json:"linkedin"`
+	Instagram   string      `json:"instagram"`
+	Facebook    string      `json:"facebook"`
+	Twitter     string      `json:"twitter"`
+}
+
+// keywordList accepts keywords as a JSON array or, when the model ignores the
+// requested shape, as a comma-separated string
+type keywordList []string
+
+// UnmarshalJSON implements json.Unmarshaler
+func (k *keywordList) UnmarshalJSON(data []byte) error {
+	var list []string
+	if err := json.Unmarshal(data, &list); err == nil {
+		*k = list
+		return nil
+	}
+
+	var joined string
+	if err := json.Unmarshal(data, &joined); err != nil {
+		// Anything else is ignored rather than failing the whole company
+		*k = nil
+		return nil
+	}
+	*k = nil
+	for _, keyword := range strings.Split(joined, ",") {
+		if keyword = strings.TrimSpace(keyword); keyword != "" {
+			*k = append(*k, keyword)
+		}
+	}
+	return nil
 }
 
 // GenerateCompanyResponse represents the response from the generate endpoint
 type GenerateCompanyResponse struct {
 	*models.Company
-	InstagramPosted  bool   `json:"instagram_posted"`
-	InstagramMediaID string `json:"instagram_media_id,omitempty"`
-	InstagramError   string `json:"instagram_error,omitempty"`
+	CandidateScore        float64 `json:"candidate_score"`
+	CandidatesConsidered  int     `json:"candidates_considered"`
+	InstagramPosted       bool    `json:"instagram_posted"`
+	InstagramMediaID      string  `json:"instagram_media_id,omitempty"`
+	InstagramPermalink    string  `json:"instagram_permalink,omitempty"`
+	InstagramError        string  `json:"instagram_error,omitempty"`
+	InstagramStoryPosted  bool    `json:"instagram_story_posted,omitempty"`
+	InstagramStoryMediaID string  `json:"instagram_story_media_id,omitempty"`
+	InstagramStoryError   string  `json:"instagram_story_error,omitempty"`
+	// InstagramQuota is the publishing quota left after posting
+	InstagramQuota *instagram.PublishingQuota `json:"instagram_quota,omitempty"`
 }
 
 // CompanyLatestHandler handles GET /companies/latest
@@ -187,12 +243,64 @@ func CompanyLatestHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	resolveCompanyURLs(r.Context(), company)
+
 	// Success - return the company
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(company)
 }
 
+// CompanyBySlugHandler handles GET /companies/{slug}
+// Returns the company with the given slug, or redirects to the current slug
+// when a previous slug of a renamed company is requested
+func CompanyBySlugHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	slug := r.PathValue("slug")
+	repo := database.NewCompanyRepository()
+
+	company, err := repo.GetBySlug(slug)
+	if err != nil && strings.Contains(err.Error(), "company not found") {
+		// Fall back to the slug history for renamed companies
+		previous, historyErr := repo.GetByPreviousSlug(slug)
+		if historyErr == nil {
+			http.Redirect(w, r, "/companies/"+previous.Slug, http.StatusMovedPermanently)
+			return
+		}
+		err = historyErr
+	}
+	if err != nil {
+		if strings.Contains(err.Error(), "company not found") {
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(ErrorResponse{
+				Error:   "not_found",
+				Message: "company not found",
+			})
+			return
+		}
+
+		log.Printf("ERROR: Failed to retrieve company %q: %v\n", slug, err)
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(ErrorResponse{
+			Error:   "internal_server_error",
+			Message: "Failed to retrieve company",
+		})
+		return
+	}
+
+	resolveCompanyURLs(r.Context(), company)
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(company)
+}
+
 // GenerateCompaniesHandler handles POST /companies/generate
 // Calls OpenAI to generate a startup, saves it to the database, and returns it
 func GenerateCompaniesHandler(w http.ResponseWriter, r *http.Request) {
@@ -201,6 +309,21 @@ func GenerateCompaniesHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Generating and publishing outlast the server's default write timeout
+	extendWriteDeadline(w)
+
+	// Parse optional generation parameters (the cron sends an empty object)
+	genReq, err := decodeGenerateRequest(w, r)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(ErrorResponse{
+			Error:   "bad_request",
+			Message: err.Error(),
+		})
+		return
+	}
+
 	// Get OpenAI API key from environment
 	apiKey := os.Getenv("OPENAI_API_KEY")
 	if apiKey == "" {
@@ -214,8 +337,38 @@ func GenerateCompaniesHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Generate company from OpenAI
-	companyData, err := generateCompanyFromAI(apiKey)
+	// Refuse to call OpenAI once a spend cap has been reached, or when the
+	// candidates about to be generated concurrently would go over it
+	prompt := buildGenerationPrompt(genReq)
+	n := candidateCount()
+	if spend, err := newSpendTracker().CheckReserve(estimateLLMCost(openAIModel, prompt, n)); err != nil {
+		if errors.Is(err, budget.ErrBudgetExceeded) {
+			log.Printf("WARNING: %v\n", err)
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.WriteHeader(http.StatusTooManyRequests)
+			json.NewEncoder(w).Encode(ErrorResponse{
+				Error:   "budget_exceeded",
+				Message: spend.Reason,
+			})
+			return
+		}
+		// Spend could not be computed (e.g. database unavailable); refuse to make
+		// paid calls that can't be counted against the caps unless opted out
+		if !llmBudgetFailOpen() {
+			log.Printf("ERROR: Failed to check LLM spend budget: %v\n", err)
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			json.NewEncoder(w).Encode(ErrorResponse{
+				Error:   "service_unavailable",
+				Message: "LLM spend budget could not be checked",
+			})
+			return
+		}
+		log.Printf("WARNING: Failed to check LLM spend budget, generating anyway: %v\n", err)
+	}
+
+	// Generate several candidates from OpenAI and keep the best one
+	best, others, err := generateBestCandidate(r.Context(), apiKey, prompt, n)
 	if err != nil {
 		log.Printf("ERROR: Failed to generate company from AI: %v\n", err)
 		w.Header().Set("Content-Type", "application/json; charset=utf-8")
@@ -227,59 +380,92 @@ func GenerateCompaniesHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Generate slug from company name
-	slug := generateSlug(companyData.Name)
+	companyData := best.Data
+
+	// Resolve slug collisions against current and previous slugs
+	repo := database.NewCompanyRepository()
+	slug, err := slugify.Unique(best.Slug, repo.SlugTaken)
+	if err != nil {
+		log.Printf("WARNING: Failed to resolve unique slug, using %q: %v\n", best.Slug, err)
+		slug = best.Slug
+	}
+
+	// Keep the losing candidates as a backlog for future days
+	saveCandidateBacklog(others, genReq)
+
+	// Capture screenshot of company website and upload it to object storage
+	objectStore := storage.GetStore()
 
-	// Capture screenshot of company website and upload to S3
-	awsRegion := os.Getenv("AWS_REGION")
-	awsAccessKey := os.Getenv("AWS_ACCESS_KEY_ID")
-	awsSecretKey := os.Getenv("AWS_SECRET_ACCESS_KEY")
-	s3Bucket := os.Getenv("S3_BUCKET_NAME")
-	screenshotAPIKey := os.Getenv("SCREENSHOTONE_API_KEY")
+	screenshotter, err := newScreenshotter()
+	if err != nil {
+		log.Printf("WARNING: Screenshot provider not available: %v\n", err)
+	}
 
 	var coverImageURL string
-	if awsRegion != "" && awsAccessKey != "" && awsSecretKey != "" && s3Bucket != "" && screenshotAPIKey != "" && companyData.Website != "" {
-		// Create S3 uploader
-		uploader, err := storage.NewS3Uploader(awsRegion, awsAccessKey, awsSecretKey, s3Bucket)
-		if err != nil {
-			log.Printf("ERROR: Failed to create S3 uploader: %v\n", err)
-			// Fall back to using the original URL from OpenAI
-			coverImageURL = companyData.CoverImage
-		} else {
-			// Capture screenshot of the website
-			ctx := r.Context()
-			screenshotBytes, err := storage.FetchScreenshotOneBytes(ctx, companyData.Website, screenshotAPIKey)
-			if err != nil {
-				log.Printf("ERROR: Failed to capture screenshot: %v\n", err)
-				// Fall back to using the original URL from OpenAI
-				coverImageURL = companyData.CoverImage
-			} else {
-				// Upload screenshot to S3
-				s3URL, err := uploader.UploadScreenshot(screenshotBytes, slug)
-				if err != nil {
-					log.Printf("ERROR: Failed to upload screenshot to S3: %v\n", err)
-					// Fall back to using the original URL from OpenAI
-					coverImageURL = companyData.CoverImage
-				} else {
-					log.Printf("Successfully captured and uploaded screenshot to S3: %s\n", s3URL)
-					coverImageURL = s3URL
-				}
+	var cardURLs map[string]string
+	var carouselURLs []string
+	var storyCardURL string
+	var coverImages []models.CoverImage
+	var uploader *storage.Uploader
+	if objectStore != nil {
+		uploader = storage.NewUploader(objectStore, remoteFetcher())
+	}
+
+	if uploader != nil && screenshotter != nil && companyData.Website != "" {
+		ctx := r.Context()
+		cardInfo := imaging.CardInfo{
+			CompanyName: companyData.Name,
+			Domain:      best.Website.Host,
+		}
+
+		// Screenshot, responsive variants and Instagram-ready cards
+		if images := captureCompanyImages(ctx, uploader, screenshotter, companyData.Website, cardInfo); images != nil {
+			coverImageURL = images.CoverURL
+			coverImages = images.CoverImages
+			cardURLs = images.CardURLs
+
+			carouselURLs = buildCarousel(ctx, uploader, cardURLs, companyData.Appeal, cardInfo)
+
+			if igStoryEnabled() {
+				storyCardURL = composeAndUploadStoryCard(ctx, uploader, images.Screenshot, cardInfo)
 			}
 		}
-	} else {
-		log.Println("WARNING: Screenshot or S3 not fully configured, using original image URL from OpenAI")
-		coverImageURL = companyData.CoverImage
+	}
+
+	// Fall back to the image URL from OpenAI
+	if coverImageURL == "" {
+		coverImageURL = mirrorCoverImage(r.Context(), uploader, companyData.CoverImage)
 	}
 
 	// Convert to a map for database insertion (only include fields we want to set)
 	// This avoids sending empty strings for auto-generated fields like ID
 	companyMap := map[string]interface{}{
-		"name":        companyData.Name,
-		"slug":        slug,
-		"website":     stripProtocol(companyData.Website),
-		"cover_image": coverImageURL,
-		"description": companyData.Description,
-		"appeal":      companyData.Appeal,
+		"name": companyData.Name,
+		"slug": slug,
+		// Store the canonical website without scheme, plus scheme and registrable domain for dedup and search
+		// and the link with the original host for screenshots and captions
+		"website":        best.Website.URL,
+		"website_scheme": best.Website.Scheme,
+		"website_link":   best.Website.Link,
+		"domain":         best.Website.Domain,
+		"cover_image":    coverImageURL,
+		"description":    companyData.Description,
+		"appeal":         companyData.Appeal,
+	}
+
+	if len(coverImages) > 0 {
+		companyMap["cover_images"] = coverImages
+	}
+	for format, cardURL := range cardURLs {
+		companyMap[cardColumns[format]] = cardURL
+	}
+
+	// Category and keywords feed the generated hashtags
+	if companyData.Category != "" {
+		companyMap["category"] = companyData.Category
+	}
+	if len(companyData.Keywords) > 0 {
+		companyMap["keywords"] = []string(companyData.Keywords)
 	}
 
 	// Add social media fields only if they're not empty
@@ -297,7 +483,6 @@ func GenerateCompaniesHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Insert into database using the map
-	repo := database.NewCompanyRepository()
 	var createdCompany *models.Company
 	createdCompany, err = repo.InsertMap(companyMap)
 	if err != nil {
@@ -311,20 +496,35 @@ func GenerateCompaniesHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Record which stored objects belong to the new company
+	if uploader != nil {
+		linkCompanyAssets(createdCompany.ID, uploader.Assets())
+	}
+
 	// Prepare response with Instagram status
 	response := GenerateCompanyResponse{
-		Company:         createdCompany,
-		InstagramPosted: false,
+		Company:              createdCompany,
+		CandidateScore:       best.Score,
+		CandidatesConsidered: len(others) + 1,
+		InstagramPosted:      false,
 	}
 
 	// Post to Instagram if enabled and configured
 	igUserID := os.Getenv("IG_USER_ID")
-	igAccessToken := os.Getenv("IG_ACCESS_TOKEN")
-	igAPIVersion := os.Getenv("IG_API_VERSION")
+	igAccessToken := igtoken.AccessToken()
 	igPostingEnabled := os.Getenv("IG_POSTING_ENABLED") != "false" // default true
 
-	if igPostingEnabled && igUserID != "" && igAccessToken != "" && coverImageURL != "" {
-		igClient := instagram.NewClient(igUserID, igAccessToken, igAPIVersion)
+	// Prefer the 4:5 portrait card, which Instagram shows uncropped
+	postImageURL := coverImageURL
+	if cardURL, ok := cardURLs[imaging.CardPortrait.Name]; ok {
+		postImageURL = cardURL
+	}
+
+	// Instagram fetches the image itself, so it needs a reachable URL
+	postImageURL = storage.ResolveURL(r.Context(), postImageURL)
+
+	if igPostingEnabled && igUserID != "" && igAccessToken != "" && postImageURL != "" {
+		igClient := newInstagramClient(igUserID, igAccessToken)
 
 		// Build caption from company data
 		// Use the original website with protocol for the caption link
@@ -332,27 +532,46 @@ func GenerateCompaniesHandler(w http.ResponseWriter, r *http.Request) {
 		if websiteForCaption == "" {
 			websiteForCaption = "startupdose.com"
 		}
-		caption := instagram.BuildCaption(
-			companyData.Name,
-			companyData.Description,
-			companyData.Appeal,
-			websiteForCaption,
-		)
-
-		// Post to Instagram
+		captionData := instagram.CaptionData{
+			Name:         companyData.Name,
+			Description:  companyData.Description,
+			Bullets:      appealBullets(companyData.Appeal),
+			Website:      websiteForCaption,
+			InstagramURL: companyData.Instagram,
+			Category:     companyData.Category,
+			Keywords:     companyData.Keywords,
+		}
+		caption := buildCaption(instagram.CaptionChannelFeed, captionData)
+		firstComment := buildFirstComment(captionData)
+
+		// Post to Instagram, as a carousel when its slides were rendered
 		ctx := r.Context()
-		result, err := igClient.PublishPost(ctx, coverImageURL, caption)
-		if err != nil {
-			log.Printf("ERROR: Instagram posting failed: %v\n", err)
-			response.InstagramError = err.Error()
-		} else if result != nil {
-			response.InstagramPosted = result.Posted
-			response.InstagramMediaID = result.MediaID
-			if result.Error != "" {
-				response.InstagramError = result.Error
-				log.Printf("WARNING: Instagram posting issue: %s\n", result.Error)
+		result := publishOnce(ctx, createdCompany.ID, models.SocialChannelInstagram, func(ctx context.Context) (*instagram.PublishResult, error) {
+			return publishFeed(ctx, igClient, carouselURLs, postImageURL, caption, firstComment)
+		})
+		response.InstagramPosted = result.Posted
+		response.InstagramMediaID = result.MediaID
+		response.InstagramPermalink = result.Permalink
+		if result.Error != "" {
+			response.InstagramError = result.Error
+			log.Printf("WARNING: Instagram posting issue: %s\n", result.Error)
+		}
+
+		// Share the company to the story as well; it links back through the card's address bar
+		// A feed post refused for quota is retried later, the story would be refused too
+		if storyCardURL != "" && !result.QuotaExhausted {
+			storyResult := publishOnce(ctx, createdCompany.ID, models.SocialChannelInstagramStory, func(ctx context.Context) (*instagram.PublishResult, error) {
+				return igClient.PublishStory(ctx, instagram.StoryMedia{ImageURL: storage.ResolveURL(ctx, storyCardURL)})
+			})
+			response.InstagramStoryPosted = storyResult.Posted
+			response.InstagramStoryMediaID = storyResult.MediaID
+			if storyResult.Error != "" {
+				response.InstagramStoryError = storyResult.Error
+				log.Printf("WARNING: Instagram story posting issue: %s\n", storyResult.Error)
 			}
 		}
+
+		response.InstagramQuota = publishingQuota(ctx, igClient)
 	} else if igPostingEnabled && (igUserID == "" || igAccessToken == "") {
 		log.Println("WARNING: Instagram posting enabled but credentials not configured")
 		response.InstagramError = "Instagram credentials not configured"
@@ -360,6 +579,8 @@ func GenerateCompaniesHandler(w http.ResponseWriter, r *http.Request) {
 		log.Println("INFO: Instagram posting disabled")
 	}
 
+	resolveCompanyURLs(r.Context(), createdCompany)
+
 	// Success - return the created company with Instagram status
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
@@ -367,7 +588,7 @@ func GenerateCompaniesHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 // generateCompanyFromAI calls the OpenAI API to generate a startup company
-func generateCompanyFromAI(apiKey string) (*CompanyFromAI, error) {
+func generateCompanyFromAI(apiKey, prompt string) (*CompanyFromAI, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
 	defer cancel()
 
@@ -377,7 +598,7 @@ func generateCompanyFromAI(apiKey string) (*CompanyFromAI, error) {
 		Messages: []OpenAIMessage{
 			{
 				Role:    "user",
-				Content: startupDosePrompt,
+				Content: prompt,
 			},
 		},
 	}
@@ -417,6 +638,13 @@ func generateCompanyFromAI(apiKey string) (*CompanyFromAI, error) {
 		return nil, fmt.Errorf("failed to decode OpenAI response: %w", err)
 	}
 
+	// Record token usage and cost for budgeting
+	model := chatResp.Model
+	if model == "" {
+		model = openAIModel
+	}
+	recordLLMUsage(model, usagePurposeCompanyGeneration, chatResp.Usage)
+
 	// Validate response structure
 	if len(chatResp.Choices) == 0 {
 		return nil, fmt.Errorf("OpenAI returned no choices")
@@ -443,26 +671,3 @@ func generateCompanyFromAI(apiKey string) (*CompanyFromAI, error) {
 
 	return &company, nil
 }
-
-// generateSlug creates a URL-friendly slug from a company name
-// Converts to lowercase, replaces spaces/special chars with hyphens
-func generateSlug(name string) string {
-	// Convert to lowercase
-	slug := strings.ToLower(name)
-
-	// Replace spaces and special characters with hyphens
-	reg := regexp.MustCompile(`[^a-z0-9]+`)
-	slug = reg.ReplaceAllString(slug, "-")
-
-	// Remove leading/trailing hyphens
-	slug = strings.Trim(slug, "-")
-
-	return slug
-}
-
-// stripProtocol removes http:// or https:// from a URL
-func stripProtocol(url string) string {
-	url = strings.TrimPrefix(url, "https://")
-	url = strings.TrimPrefix(url, "http://")
-	return url
-}
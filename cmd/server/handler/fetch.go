@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"startupdose.com/cmd/server/fetch"
+)
+
+var (
+	remoteFetcherOnce sync.Once
+	remoteFetcherInst *fetch.Fetcher
+)
+
+// remoteFetcher returns the shared fetcher for AI-supplied URLs, configured
+// from REMOTE_FETCH_TIMEOUT, REMOTE_FETCH_MAX_BYTES and REMOTE_FETCH_MAX_REDIRECTS
+func remoteFetcher() *fetch.Fetcher {
+	remoteFetcherOnce.Do(func() {
+		var opts fetch.Options
+
+		if v := os.Getenv("REMOTE_FETCH_TIMEOUT"); v != "" {
+			if d, err := time.ParseDuration(v); err == nil && d > 0 {
+				opts.Timeout = d
+			} else {
+				log.Printf("WARNING: Invalid REMOTE_FETCH_TIMEOUT %q, using default\n", v)
+			}
+		}
+		if v := os.Getenv("REMOTE_FETCH_MAX_BYTES"); v != "" {
+			if n, err := strconv.ParseInt(v, 10, 64); err == nil && n > 0 {
+				opts.MaxBytes = n
+			} else {
+				log.Printf("WARNING: Invalid REMOTE_FETCH_MAX_BYTES %q, using default\n", v)
+			}
+		}
+		if v := os.Getenv("REMOTE_FETCH_MAX_REDIRECTS"); v != "" {
+			if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+				opts.MaxRedirects = n
+				if n == 0 {
+					opts.MaxRedirects = -1 // Options treats 0 as "use the default"
+				}
+			} else {
+				log.Printf("WARNING: Invalid REMOTE_FETCH_MAX_REDIRECTS %q, using default\n", v)
+			}
+		}
+
+		remoteFetcherInst = fetch.New(opts)
+	})
+	return remoteFetcherInst
+}
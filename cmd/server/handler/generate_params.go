@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Limits for the generate request body
+const (
+	maxGenerateBodyBytes = 16 << 10
+	maxParamLength       = 80
+	maxThemeLength       = 120
+	maxExcludeEntries    = 25
+)
+
+// allowedStages lists the funding stages accepted in the "stage" field
+var allowedStages = map[string]string{
+	"pre-seed":     "pre-seed",
+	"seed":         "seed",
+	"series-a":     "Series A",
+	"series-b":     "Series B",
+	"series-c":     "Series C or later",
+	"growth":       "growth stage",
+	"bootstrapped": "bootstrapped (no outside funding)",
+}
+
+// GenerateCompanyRequest represents the optional JSON body of POST /companies/generate
+// All fields are optional; an empty body or {} keeps the default prompt
+type GenerateCompanyRequest struct {
+	Category string   `json:"category"`
+	Region   string   `json:"region"`
+	Stage    string   `json:"stage"`
+	Exclude  []string `json:"exclude"`
+	Theme    string   `json:"theme"`
+}
+
+// decodeGenerateRequest reads and validates the request body
+// Returns a zero-value request when the body is empty
+func decodeGenerateRequest(w http.ResponseWriter, r *http.Request) (*GenerateCompanyRequest, error) {
+	req := &GenerateCompanyRequest{}
+	if r.Body == nil {
+		return req, nil
+	}
+
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxGenerateBodyBytes))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(req); err != nil {
+		if errors.Is(err, io.EOF) {
+			return req, nil
+		}
+		return nil, fmt.Errorf("invalid JSON body: %w", err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// Validate normalizes the fields and checks lengths and allowed values
+func (g *GenerateCompanyRequest) Validate() error {
+	g.Category = strings.TrimSpace(g.Category)
+	g.Region = strings.TrimSpace(g.Region)
+	g.Stage = strings.ToLower(strings.TrimSpace(g.Stage))
+	g.Theme = strings.TrimSpace(g.Theme)
+
+	if err := validateParam("category", g.Category, maxParamLength); err != nil {
+		return err
+	}
+	if err := validateParam("region", g.Region, maxParamLength); err != nil {
+		return err
+	}
+	if err := validateParam("theme", g.Theme, maxThemeLength); err != nil {
+		return err
+	}
+
+	if g.Stage != "" {
+		if _, ok := allowedStages[g.Stage]; !ok {
+			stages := make([]string, 0, len(allowedStages))
+			for stage := range allowedStages {
+				stages = append(stages, stage)
+			}
+			sort.Strings(stages)
+			return fmt.Errorf("stage must be one of: %s", strings.Join(stages, ", "))
+		}
+	}
+
+	if len(g.Exclude) > maxExcludeEntries {
+		return fmt.Errorf("exclude may contain at most %d entries", maxExcludeEntries)
+	}
+	exclude := make([]string, 0, len(g.Exclude))
+	for _, name := range g.Exclude {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if err := validateParam("exclude entry", name, maxParamLength); err != nil {
+			return err
+		}
+		exclude = append(exclude, name)
+	}
+	g.Exclude = exclude
+
+	return nil
+}
+
+// IsEmpty returns true when no generation parameters were provided
+func (g *GenerateCompanyRequest) IsEmpty() bool {
+	return g.Category == "" && g.Region == "" && g.Stage == "" && g.Theme == "" && len(g.Exclude) == 0
+}
+
+// validateParam rejects values that are too long or contain characters that could
+// break out of the prompt (newlines, quotes, markup)
+func validateParam(field, value string, maxLength int) error {
+	if len([]rune(value)) > maxLength {
+		return fmt.Errorf("%s must be at most %d characters", field, maxLength)
+	}
+	for _, r := range value {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == ' ' {
+			continue
+		}
+		if strings.ContainsRune("-&/.,'()+#!", r) {
+			continue
+		}
+		return fmt.Errorf("%s contains an invalid character %q", field, r)
+	}
+	return nil
+}
+
+// buildGenerationPrompt appends the requested constraints to the base prompt
+func buildGenerationPrompt(g *GenerateCompanyRequest) string {
+	if g == nil || g.IsEmpty() {
+		return startupDosePrompt
+	}
+
+	var b strings.Builder
+	b.WriteString(startupDosePrompt)
+	b.WriteString("\n\nAdditional requirements for today's pick (these take priority over the general guidance above, but the startup must still be lesser-known and active):\n")
+	if g.Theme != "" {
+		fmt.Fprintf(&b, "\n* Today's theme is %q. Pick a startup that clearly fits this theme.", g.Theme)
+	}
+	if g.Category != "" {
+		fmt.Fprintf(&b, "\n* The startup MUST be in this category: %q.", g.Category)
+	}
+	if g.Region != "" {
+		fmt.Fprintf(&b, "\n* The startup MUST be headquartered in this region: %q.", g.Region)
+	}
+	if g.Stage != "" {
+		fmt.Fprintf(&b, "\n* The startup MUST be at this funding stage: %s.", allowedStages[g.Stage])
+	}
+	if len(g.Exclude) > 0 {
+		quoted := make([]string, len(g.Exclude))
+		for i, name := range g.Exclude {
+			quoted[i] = fmt.Sprintf("%q", name)
+		}
+		fmt.Fprintf(&b, "\n* Do NOT pick any of these companies: %s.", strings.Join(quoted, ", "))
+	}
+
+	return b.String()
+}
@@ -3,10 +3,16 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"log"
 	"net/http"
+	"os"
+	"sort"
 	"time"
 
+	"github.com/google/uuid"
 	"startupdose.com/cmd/server/client"
+	"startupdose.com/cmd/server/database"
+	"startupdose.com/cmd/server/igtoken"
 )
 
 // ErrorResponse represents a JSON http.Error response
@@ -15,9 +21,54 @@ type ErrorResponse struct {
 	Message string `json:"message"`
 }
 
+// longRequestWriteTimeout replaces the server's write timeout for requests that
+// generate or publish content, which call OpenAI, capture screenshots and wait
+// for Instagram to process media
+const longRequestWriteTimeout = 10 * time.Minute
+
+// extendWriteDeadline gives a long-running request more time to write its response
+func extendWriteDeadline(w http.ResponseWriter) {
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Now().Add(longRequestWriteTimeout)); err != nil {
+		log.Printf("WARNING: Failed to extend write deadline: %v\n", err)
+	}
+}
+
+// parseUUID returns the canonical form of a UUID path value; ok is false when
+// it isn't one, so lookups never send Postgres a malformed uuid
+func parseUUID(value string) (id string, ok bool) {
+	parsed, err := uuid.Parse(value)
+	if err != nil {
+		return "", false
+	}
+	return parsed.String(), true
+}
+
 // HealthResponse represents a health check response
 type HealthResponse struct {
 	OK bool `json:"ok"`
+	// Warnings lists degraded dependencies that don't stop the server from working
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// Readiness check statuses
+const (
+	checkStatusOK   = "ok"
+	checkStatusWarn = "warn"
+	checkStatusFail = "fail"
+)
+
+// ReadinessCheck is the outcome of one readiness check
+type ReadinessCheck struct {
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+	// Details carries check-specific data such as the Instagram token status
+	Details interface{} `json:"details,omitempty"`
+}
+
+// ReadinessResponse represents a readiness check response
+type ReadinessResponse struct {
+	Ready  bool                      `json:"ready"`
+	Checks map[string]ReadinessCheck `json:"checks"`
 }
 
 // PostsHandler handles GET /posts/1
@@ -89,7 +140,68 @@ func HealthzHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Liveness never fails on dependencies, but surfaces their warnings
+	var warnings []string
+	for name, check := range readinessChecks() {
+		if check.Status != checkStatusOK {
+			warnings = append(warnings, name+": "+check.Message)
+		}
+	}
+	sort.Strings(warnings)
+
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(HealthResponse{OK: true})
+	json.NewEncoder(w).Encode(HealthResponse{OK: true, Warnings: warnings})
+}
+
+// ReadyzHandler handles GET /readyz
+// Returns 503 when a dependency the API cannot serve without is unavailable;
+// degraded dependencies such as an expiring Instagram token are reported as warnings
+func ReadyzHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	checks := readinessChecks()
+	ready := true
+	for _, check := range checks {
+		if check.Status == checkStatusFail {
+			ready = false
+		}
+	}
+
+	status := http.StatusOK
+	if !ready {
+		status = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(ReadinessResponse{Ready: ready, Checks: checks})
+}
+
+// readinessChecks inspects the database and the Instagram token
+func readinessChecks() map[string]ReadinessCheck {
+	checks := make(map[string]ReadinessCheck)
+
+	if database.GetClient() == nil {
+		checks["database"] = ReadinessCheck{Status: checkStatusFail, Message: "database client not initialized"}
+	} else {
+		checks["database"] = ReadinessCheck{Status: checkStatusOK}
+	}
+
+	if os.Getenv("IG_POSTING_ENABLED") != "false" {
+		if manager := igtoken.Get(); manager != nil {
+			tokenStatus := manager.Status()
+			check := ReadinessCheck{Status: checkStatusOK, Details: tokenStatus}
+			if !tokenStatus.Healthy {
+				check.Status = checkStatusWarn
+				check.Message = tokenStatus.Reason
+			}
+			checks["instagram_token"] = check
+		}
+	}
+
+	return checks
 }
@@ -0,0 +1,190 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"startupdose.com/cmd/server/igtoken"
+	"startupdose.com/cmd/server/instagram"
+)
+
+var (
+	captionBuilderOnce sync.Once
+	captionBuilderInst *instagram.CaptionBuilder
+)
+
+// newInstagramClient creates a client polling containers as configured by
+// IG_POLL_INITIAL_DELAY, IG_POLL_MAX_DELAY, IG_POLL_MAX_ATTEMPTS and
+// IG_VIDEO_POLL_MAX_ATTEMPTS; unset or invalid values use the defaults
+func newInstagramClient(userID, accessToken string) *instagram.Client {
+	var poll instagram.PollConfig
+	poll.InitialDelay = envDuration("IG_POLL_INITIAL_DELAY")
+	poll.MaxDelay = envDuration("IG_POLL_MAX_DELAY")
+	poll.MaxAttempts = envPositiveInt("IG_POLL_MAX_ATTEMPTS")
+	poll.VideoMaxAttempts = envPositiveInt("IG_VIDEO_POLL_MAX_ATTEMPTS")
+
+	return instagram.NewClient(userID, accessToken, os.Getenv("IG_API_VERSION")).WithPollConfig(poll)
+}
+
+// envDuration parses a positive duration from key, or returns 0
+func envDuration(key string) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		log.Printf("WARNING: Invalid %s %q, using default\n", key, v)
+		return 0
+	}
+	return d
+}
+
+// envPositiveInt parses a positive integer from key, or returns 0
+func envPositiveInt(key string) int {
+	v := os.Getenv(key)
+	if v == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n <= 0 {
+		log.Printf("WARNING: Invalid %s %q, using default\n", key, v)
+		return 0
+	}
+	return n
+}
+
+// captionBuilder returns the shared caption builder, configured from
+// IG_CAPTION_TEMPLATES_DIR (one <channel>.tmpl file per channel),
+// IG_CAPTION_HASHTAGS, IG_CAPTION_MAX_HASHTAGS and IG_HASHTAGS_FIRST_COMMENT
+// Invalid templates are logged and the defaults used instead
+func captionBuilder() *instagram.CaptionBuilder {
+	captionBuilderOnce.Do(func() {
+		var opts instagram.CaptionOptions
+
+		if dir := os.Getenv("IG_CAPTION_TEMPLATES_DIR"); dir != "" {
+			opts.Templates = loadCaptionTemplates(dir)
+		}
+		if v := os.Getenv("IG_CAPTION_HASHTAGS"); v != "" {
+			opts.Hashtags = strings.FieldsFunc(v, func(r rune) bool {
+				return r == ',' || r == ' '
+			})
+		}
+		opts.MaxHashtags = envPositiveInt("IG_CAPTION_MAX_HASHTAGS")
+		opts.FirstComment = os.Getenv("IG_HASHTAGS_FIRST_COMMENT") == "true"
+
+		builder, err := instagram.NewCaptionBuilder(opts)
+		if err != nil {
+			log.Printf("WARNING: %v, using default caption templates\n", err)
+			opts.Templates = nil
+			builder, _ = instagram.NewCaptionBuilder(opts)
+		}
+		captionBuilderInst = builder
+	})
+	return captionBuilderInst
+}
+
+// loadCaptionTemplates reads the *.tmpl files in dir, keyed by file name without extension
+func loadCaptionTemplates(dir string) map[string]string {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.tmpl"))
+	if err != nil || len(paths) == 0 {
+		log.Printf("WARNING: No caption templates found in IG_CAPTION_TEMPLATES_DIR %q, using defaults\n", dir)
+		return nil
+	}
+
+	templates := make(map[string]string, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			log.Printf("WARNING: Failed to read caption template %s: %v\n", path, err)
+			continue
+		}
+		templates[strings.TrimSuffix(filepath.Base(path), ".tmpl")] = string(data)
+	}
+	return templates
+}
+
+// buildCaption renders a caption for channel
+// A template that fails to render is logged and the default template used instead
+func buildCaption(channel string, data instagram.CaptionData) string {
+	caption, err := captionBuilder().Build(channel, data)
+	if err == nil {
+		return caption
+	}
+
+	log.Printf("WARNING: %v, using default caption template\n", err)
+	fallback, _ := instagram.NewCaptionBuilder(instagram.CaptionOptions{
+		FirstComment: os.Getenv("IG_HASHTAGS_FIRST_COMMENT") == "true",
+	})
+	caption, err = fallback.Build(channel, data)
+	if err != nil {
+		log.Printf("ERROR: Failed to render default caption: %v\n", err)
+		return data.Name
+	}
+	return caption
+}
+
+// buildFirstComment returns the hashtags to post as the first comment, or ""
+// when they are part of the caption
+func buildFirstComment(data instagram.CaptionData) string {
+	return captionBuilder().FirstComment(data)
+}
+
+// publishingQuota returns the account's publishing quota, or nil when it can't be read
+func publishingQuota(ctx context.Context, igClient *instagram.Client) *instagram.PublishingQuota {
+	quota, err := igClient.PublishingQuota(ctx)
+	if err != nil {
+		log.Printf("WARNING: Failed to read Instagram publishing quota: %v\n", err)
+		return nil
+	}
+	return quota
+}
+
+// InstagramStatusResponse represents the response from the Instagram status endpoint
+type InstagramStatusResponse struct {
+	PostingEnabled bool            `json:"posting_enabled"`
+	Token          *igtoken.Status `json:"token,omitempty"`
+	// Quota is nil when the credentials are missing or the quota can't be read
+	Quota      *instagram.PublishingQuota `json:"quota,omitempty"`
+	QuotaError string                     `json:"quota_error,omitempty"`
+}
+
+// InstagramStatusHandler handles GET /admin/instagram/status
+// Reports the access token's health and the remaining publishing quota
+func InstagramStatusHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	response := InstagramStatusResponse{
+		PostingEnabled: os.Getenv("IG_POSTING_ENABLED") != "false",
+	}
+	if manager := igtoken.Get(); manager != nil {
+		status := manager.Status()
+		response.Token = &status
+	}
+
+	igUserID := os.Getenv("IG_USER_ID")
+	igAccessToken := igtoken.AccessToken()
+	if igUserID == "" || igAccessToken == "" {
+		response.QuotaError = "Instagram credentials not configured"
+	} else if quota, err := newInstagramClient(igUserID, igAccessToken).PublishingQuota(r.Context()); err != nil {
+		log.Printf("WARNING: Failed to read Instagram publishing quota: %v\n", err)
+		response.QuotaError = err.Error()
+	} else {
+		response.Quota = quota
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"startupdose.com/cmd/server/imaging"
+	"startupdose.com/cmd/server/screenshot"
+)
+
+// newScreenshotter builds the screenshot provider selected by SCREENSHOT_PROVIDER
+func newScreenshotter() (screenshot.Screenshotter, error) {
+	return screenshot.New(screenshot.Config{
+		Provider:            os.Getenv("SCREENSHOT_PROVIDER"),
+		ScreenshotOneAPIKey: os.Getenv("SCREENSHOTONE_API_KEY"),
+		ChromePath:          os.Getenv("CHROME_PATH"),
+	})
+}
+
+// screenshotOptionsFromEnv returns the capture options for a provider: the
+// defaults, overridden by the shared SCREENSHOT_* variables, overridden in turn
+// by that provider's SCREENSHOT_<PROVIDER>_* variables
+// (e.g. SCREENSHOT_CHROME_DELAY or SCREENSHOT_SCREENSHOTONE_FORMAT)
+func screenshotOptionsFromEnv(provider string) screenshot.Options {
+	opts := screenshot.DefaultOptions()
+	applyScreenshotEnv(&opts, "SCREENSHOT_")
+	if provider != "" {
+		applyScreenshotEnv(&opts, "SCREENSHOT_"+strings.ToUpper(provider)+"_")
+	}
+	return opts
+}
+
+// applyScreenshotEnv overrides opts with the variables set under prefix
+func applyScreenshotEnv(opts *screenshot.Options, prefix string) {
+	if v := os.Getenv(prefix + "VIEWPORT_WIDTH"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			opts.ViewportWidth = n
+		} else {
+			log.Printf("WARNING: Invalid %sVIEWPORT_WIDTH %q, ignoring\n", prefix, v)
+		}
+	}
+	if v := os.Getenv(prefix + "VIEWPORT_HEIGHT"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			opts.ViewportHeight = n
+		} else {
+			log.Printf("WARNING: Invalid %sVIEWPORT_HEIGHT %q, ignoring\n", prefix, v)
+		}
+	}
+	if v := os.Getenv(prefix + "DEVICE_SCALE_FACTOR"); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil && f > 0 {
+			opts.DeviceScaleFactor = f
+		} else {
+			log.Printf("WARNING: Invalid %sDEVICE_SCALE_FACTOR %q, ignoring\n", prefix, v)
+		}
+	}
+	if v := os.Getenv(prefix + "FORMAT"); v != "" {
+		opts.Format = strings.ToLower(v)
+	}
+	if v := os.Getenv(prefix + "DELAY"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+			opts.Delay = d
+		} else {
+			log.Printf("WARNING: Invalid %sDELAY %q, ignoring\n", prefix, v)
+		}
+	}
+	// A provider's selectors replace the shared ones
+	if v := os.Getenv(prefix + "HIDE_SELECTORS"); v != "" {
+		opts.HiddenSelectors = nil
+		for _, selector := range strings.Split(v, ",") {
+			if selector = strings.TrimSpace(selector); selector != "" {
+				opts.HiddenSelectors = append(opts.HiddenSelectors, selector)
+			}
+		}
+	}
+	if v := os.Getenv(prefix + "BLOCK_COOKIE_BANNERS"); v != "" {
+		opts.BlockCookieBanners = v != "false"
+	}
+}
+
+// Maximum number of captures per company before falling back to another image
+const maxScreenshotAttempts = 3
+
+// retryHiddenSelectors are consent walls and overlays hidden when retrying
+// after an unusable capture
+var retryHiddenSelectors = []string{
+	"#onetrust-consent-sdk",
+	"#CybotCookiebotDialog",
+	"#usercentrics-root",
+	".cc-window",
+	"[class*='cookie-banner']",
+	"[id*='cookie-consent']",
+	"[aria-modal='true']",
+	"#intercom-container",
+}
+
+var (
+	badTemplatesOnce sync.Once
+	badTemplates     []imaging.Template
+)
+
+// badScreenshotTemplates loads the known-bad page templates from
+// SCREENSHOT_BAD_TEMPLATES_DIR (images) and SCREENSHOT_BAD_TEMPLATE_HASHES (name=hex dHash)
+func badScreenshotTemplates() []imaging.Template {
+	badTemplatesOnce.Do(func() {
+		if dir := os.Getenv("SCREENSHOT_BAD_TEMPLATES_DIR"); dir != "" {
+			templates, err := imaging.LoadTemplates(dir)
+			if err != nil {
+				log.Printf("WARNING: Failed to load bad screenshot templates: %v\n", err)
+			}
+			badTemplates = append(badTemplates, templates...)
+		}
+		if raw := os.Getenv("SCREENSHOT_BAD_TEMPLATE_HASHES"); raw != "" {
+			templates, err := imaging.ParseTemplateHashes(raw)
+			if err != nil {
+				log.Printf("WARNING: Invalid SCREENSHOT_BAD_TEMPLATE_HASHES: %v\n", err)
+			}
+			badTemplates = append(badTemplates, templates...)
+		}
+	})
+	return badTemplates
+}
+
+// screenshotAttempts returns the capture options tried in order: the configured
+// options, then a slower capture with overlays hidden (challenge pages often
+// clear after a few seconds), then a mobile viewport
+func screenshotAttempts(base screenshot.Options) []screenshot.Options {
+	patient := base
+	patient.BlockCookieBanners = true
+	patient.HiddenSelectors = append(append([]string(nil), base.HiddenSelectors...), retryHiddenSelectors...)
+	if patient.Delay < 5*time.Second {
+		patient.Delay = 5 * time.Second
+	}
+
+	mobile := patient
+	mobile.ViewportWidth = 390
+	mobile.ViewportHeight = 844
+	mobile.DeviceScaleFactor = 3
+
+	return []screenshot.Options{base, patient, mobile}[:maxScreenshotAttempts]
+}
+
+// captureUsableScreenshot captures a website, rejecting blank pages, cookie
+// walls and bot challenges and retrying with different options
+// Returns an error when no attempt produced a usable screenshot
+func captureUsableScreenshot(ctx context.Context, s screenshot.Screenshotter, websiteURL string) ([]byte, error) {
+	templates := badScreenshotTemplates()
+
+	var lastErr error
+	for i, opts := range screenshotAttempts(screenshotOptionsFromEnv(s.Name())) {
+		data, err := s.Capture(ctx, websiteURL, opts)
+		if err != nil {
+			log.Printf("WARNING: Screenshot attempt %d for %s failed: %v\n", i+1, websiteURL, err)
+			lastErr = err
+			if ctx.Err() != nil {
+				break
+			}
+			continue
+		}
+
+		analysis, err := imaging.AnalyzeScreenshot(data, templates)
+		if err != nil {
+			log.Printf("WARNING: Screenshot attempt %d for %s could not be analyzed: %v\n", i+1, websiteURL, err)
+			lastErr = err
+			continue
+		}
+		if analysis.Usable {
+			return data, nil
+		}
+
+		log.Printf("WARNING: Screenshot attempt %d for %s unusable: %s (dhash %016x)\n", i+1, websiteURL, analysis.Reason, analysis.Hash)
+		lastErr = fmt.Errorf("unusable screenshot: %s", analysis.Reason)
+	}
+
+	return nil, fmt.Errorf("no usable screenshot after %d attempts: %w", maxScreenshotAttempts, lastErr)
+}
@@ -0,0 +1,449 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"startupdose.com/cmd/server/database"
+	"startupdose.com/cmd/server/igtoken"
+	"startupdose.com/cmd/server/imaging"
+	"startupdose.com/cmd/server/instagram"
+	"startupdose.com/cmd/server/models"
+	"startupdose.com/cmd/server/storage"
+)
+
+// publishOnce publishes a company to a social channel at most once
+// The channel is claimed in social_posts first; publish only runs when this
+// request wins the claim, otherwise the recorded outcome is returned. If the
+// claim cannot be recorded nothing is published, since posting without a
+// record is how duplicates happen.
+func publishOnce(ctx context.Context, companyID, channel string, publish func(context.Context) (*instagram.PublishResult, error)) *instagram.PublishResult {
+	repo := database.NewSocialPostRepository()
+	post, claimed, err := repo.Claim(companyID, channel)
+	if err != nil {
+		log.Printf("ERROR: Failed to claim %s post for company %s: %v\n", channel, companyID, err)
+		return &instagram.PublishResult{Posted: false, Error: fmt.Sprintf("failed to record social post: %v", err)}
+	}
+	if !claimed {
+		log.Printf("INFO: %s post for company %s already %s, not posting again\n", channel, companyID, post.Status)
+		return recordedResult(post)
+	}
+
+	return publishClaimed(ctx, repo, post, publish)
+}
+
+// publishClaimed runs publish for a claimed post and records the outcome
+func publishClaimed(ctx context.Context, repo *database.SocialPostRepository, post *models.SocialPost, publish func(context.Context) (*instagram.PublishResult, error)) *instagram.PublishResult {
+	result, err := publish(ctx)
+	if err != nil {
+		result = &instagram.PublishResult{Posted: false, Error: err.Error()}
+	} else if result == nil {
+		result = &instagram.PublishResult{Posted: false, Error: "no publish result returned"}
+	}
+
+	if err := repo.Complete(post.ID, result.Posted, result.ContainerID, result.MediaID, result.Permalink, result.Error); err != nil {
+		log.Printf("ERROR: Failed to record %s post outcome for company %s: %v\n", post.Channel, post.CompanyID, err)
+	}
+
+	return result
+}
+
+// recordedResult describes an existing social post as a publish result
+func recordedResult(post *models.SocialPost) *instagram.PublishResult {
+	result := &instagram.PublishResult{Posted: post.Status == models.SocialPostStatusPosted}
+	if post.MediaID != nil {
+		result.MediaID = *post.MediaID
+	}
+	if post.ContainerID != nil {
+		result.ContainerID = *post.ContainerID
+	}
+	if post.Permalink != nil {
+		result.Permalink = *post.Permalink
+	}
+
+	switch post.Status {
+	case models.SocialPostStatusPosting:
+		result.Error = "already being posted by another request"
+	case models.SocialPostStatusFailed:
+		result.Error = "previous attempt failed"
+		if post.Error != nil {
+			result.Error += ": " + *post.Error
+		}
+	}
+	return result
+}
+
+// publishFeed publishes a feed post, as a carousel when slides were rendered
+// A non-empty firstComment is posted as the first comment once the post is
+// live; failing to comment is logged and doesn't fail the post
+func publishFeed(ctx context.Context, igClient *instagram.Client, carouselURLs []string, imageURL, caption, firstComment string) (*instagram.PublishResult, error) {
+	var result *instagram.PublishResult
+	var err error
+	if len(carouselURLs) > 0 {
+		slideURLs := make([]string, len(carouselURLs))
+		for i, slideURL := range carouselURLs {
+			slideURLs[i] = storage.ResolveURL(ctx, slideURL)
+		}
+		result, err = igClient.PublishCarousel(ctx, slideURLs, caption)
+	} else {
+		result, err = igClient.PublishPost(ctx, imageURL, caption)
+	}
+
+	if err == nil && result.Posted && firstComment != "" {
+		if _, err := igClient.Comment(ctx, result.MediaID, firstComment); err != nil {
+			log.Printf("WARNING: Failed to post first comment on media %s: %v\n", result.MediaID, err)
+		}
+	}
+	return result, err
+}
+
+// companyWebsiteURL returns a stored company's website with its scheme,
+// preferring the link that keeps the host as generated
+func companyWebsiteURL(company *models.Company) string {
+	if company.WebsiteLink != "" {
+		return company.WebsiteLink
+	}
+	if company.Website == "" || strings.Contains(company.Website, "://") {
+		return company.Website
+	}
+	scheme := company.WebsiteScheme
+	if scheme == "" {
+		scheme = "https"
+	}
+	return scheme + "://" + company.Website
+}
+
+// InstagramRetryResponse represents the response from the Instagram retry endpoint
+type InstagramRetryResponse struct {
+	CompanyID string `json:"company_id"`
+	Attempts  int    `json:"attempts"`
+	Posted    bool   `json:"posted"`
+	MediaID   string `json:"media_id,omitempty"`
+	Permalink string `json:"permalink,omitempty"`
+	// ImageRefreshed is true when the post image was re-captured or re-mirrored
+	ImageRefreshed bool   `json:"image_refreshed"`
+	Error          string `json:"error,omitempty"`
+	// Quota is the publishing quota left after the attempt
+	Quota *instagram.PublishingQuota `json:"quota,omitempty"`
+}
+
+// InstagramRetryHandler handles POST /companies/{id}/social/instagram/retry
+// Republishes a company whose Instagram post failed, rebuilding the caption
+// from the stored company and reusing its image when Instagram can still
+// fetch it, or re-capturing it otherwise. Pass ?refresh_image=true to always
+// re-capture, and ?force=true to retry a post stuck in "posting" after
+// checking it never reached Instagram. Responds 429 while the account's
+// publishing quota is exhausted.
+func InstagramRetryHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Generating and publishing outlast the server's default write timeout
+	extendWriteDeadline(w)
+
+	query := r.URL.Query()
+	force := query.Get("force") == "true"
+	refreshImage := query.Get("refresh_image") == "true"
+
+	igUserID := os.Getenv("IG_USER_ID")
+	igAccessToken := igtoken.AccessToken()
+	if os.Getenv("IG_POSTING_ENABLED") == "false" || igUserID == "" || igAccessToken == "" {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(ErrorResponse{
+			Error:   "instagram_unavailable",
+			Message: "Instagram posting is disabled or credentials are not configured",
+		})
+		return
+	}
+
+	// A malformed id can't match any company
+	id, ok := parseUUID(r.PathValue("id"))
+	if !ok {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(ErrorResponse{
+			Error:   "not_found",
+			Message: "company not found",
+		})
+		return
+	}
+
+	company, err := database.NewCompanyRepository().GetByID(id)
+	if err != nil {
+		if strings.Contains(err.Error(), "company not found") {
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(ErrorResponse{
+				Error:   "not_found",
+				Message: "company not found",
+			})
+			return
+		}
+
+		log.Printf("ERROR: Failed to retrieve company %q: %v\n", id, err)
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(ErrorResponse{
+			Error:   "internal_server_error",
+			Message: "Failed to retrieve company",
+		})
+		return
+	}
+
+	// Reject retries that can't proceed before spending time on images
+	repo := database.NewSocialPostRepository()
+	existing, err := repo.Get(company.ID, models.SocialChannelInstagram)
+	if err != nil {
+		log.Printf("ERROR: Failed to load Instagram post for company %s: %v\n", company.ID, err)
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(ErrorResponse{
+			Error:   "internal_server_error",
+			Message: "Failed to load social post",
+		})
+		return
+	}
+	if conflict := retryConflict(existing, force); conflict != "" {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(ErrorResponse{
+			Error:   "conflict",
+			Message: conflict,
+		})
+		return
+	}
+
+	// Refuse while the publishing quota is used up instead of recording another failure
+	ctx := r.Context()
+	igClient := newInstagramClient(igUserID, igAccessToken)
+	if quota := publishingQuota(ctx, igClient); quota != nil && quota.Exhausted() {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusTooManyRequests)
+		json.NewEncoder(w).Encode(ErrorResponse{
+			Error:   "quota_exhausted",
+			Message: fmt.Sprintf("Instagram publishing quota exhausted: %d of %d posts used in the last %d hours", quota.Used, quota.Total, quota.WindowHours),
+		})
+		return
+	}
+
+	images := prepareRetryImages(ctx, company, refreshImage)
+	if images.PostImageURL == "" {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(ErrorResponse{
+			Error:   "no_image",
+			Message: "No image available to post for this company",
+		})
+		return
+	}
+
+	// Claim the channel; a concurrent retry that got there first wins
+	var post *models.SocialPost
+	var claimed bool
+	if existing == nil {
+		post, claimed, err = repo.Claim(company.ID, models.SocialChannelInstagram)
+	} else {
+		post, claimed, err = repo.Reclaim(existing)
+	}
+	if err != nil {
+		log.Printf("ERROR: Failed to claim Instagram post for company %s: %v\n", company.ID, err)
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(ErrorResponse{
+			Error:   "internal_server_error",
+			Message: "Failed to record social post",
+		})
+		return
+	}
+	if !claimed {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(ErrorResponse{
+			Error:   "conflict",
+			Message: "Instagram post was claimed by another request",
+		})
+		return
+	}
+
+	var instagramURL string
+	if company.Instagram != nil {
+		instagramURL = *company.Instagram
+	}
+	captionData := instagram.CaptionData{
+		Name:         company.Name,
+		Description:  company.Description,
+		Bullets:      appealBullets(company.Appeal),
+		Website:      captionWebsite(company),
+		InstagramURL: instagramURL,
+		Category:     company.Category,
+		Keywords:     company.Keywords,
+	}
+	caption := buildCaption(instagram.CaptionChannelFeed, captionData)
+	firstComment := buildFirstComment(captionData)
+	postImageURL := storage.ResolveURL(ctx, images.PostImageURL)
+
+	log.Printf("INFO: Retrying Instagram post for company %s (attempt %d)\n", company.ID, post.Attempts)
+	result := publishClaimed(ctx, repo, post, func(ctx context.Context) (*instagram.PublishResult, error) {
+		return publishFeed(ctx, igClient, images.CarouselURLs, postImageURL, caption, firstComment)
+	})
+
+	response := InstagramRetryResponse{
+		CompanyID:      company.ID,
+		Attempts:       post.Attempts,
+		Posted:         result.Posted,
+		MediaID:        result.MediaID,
+		Permalink:      result.Permalink,
+		ImageRefreshed: images.Refreshed,
+		Error:          result.Error,
+		Quota:          publishingQuota(ctx, igClient),
+	}
+
+	status := http.StatusOK
+	if !result.Posted {
+		log.Printf("WARNING: Instagram retry for company %s failed: %s\n", company.ID, result.Error)
+		status = http.StatusBadGateway
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
+
+// retryConflict explains why a recorded post can't be retried, or returns ""
+func retryConflict(post *models.SocialPost, force bool) string {
+	if post == nil {
+		return ""
+	}
+	switch post.Status {
+	case models.SocialPostStatusPosted:
+		return "Instagram post already published"
+	case models.SocialPostStatusPosting:
+		if !force {
+			return "Instagram post is in progress; pass force=true if it is stuck and never reached Instagram"
+		}
+	}
+	return ""
+}
+
+// captionWebsite returns the link shown in a caption
+func captionWebsite(company *models.Company) string {
+	if website := companyWebsiteURL(company); website != "" {
+		return website
+	}
+	return "startupdose.com"
+}
+
+// retryImages are the images used to republish a company
+type retryImages struct {
+	PostImageURL string
+	CarouselURLs []string
+	// Refreshed is true when the company's stored images were replaced
+	Refreshed bool
+}
+
+// prepareRetryImages picks the image to republish, preferring the portrait
+// card. When the image is no longer in storage, is an external URL, or
+// refresh is set, the website is screenshotted again (falling back to
+// mirroring the external cover) and the company is updated with the new images.
+func prepareRetryImages(ctx context.Context, company *models.Company, refresh bool) retryImages {
+	var images retryImages
+
+	var uploader *storage.Uploader
+	if store := storage.GetStore(); store != nil {
+		uploader = storage.NewUploader(store, remoteFetcher())
+	}
+
+	cardURLs := make(map[string]string)
+	if company.SquareCard != nil && *company.SquareCard != "" {
+		cardURLs[imaging.CardSquare.Name] = *company.SquareCard
+	}
+	if company.PortraitCard != nil && *company.PortraitCard != "" {
+		cardURLs[imaging.CardPortrait.Name] = *company.PortraitCard
+	}
+	postImageURL := company.CoverImage
+	if cardURL, ok := cardURLs[imaging.CardPortrait.Name]; ok {
+		postImageURL = cardURL
+	}
+
+	website := companyWebsiteURL(company)
+	info := imaging.CardInfo{CompanyName: company.Name}
+	if u, err := url.Parse(website); err == nil {
+		info.Domain = u.Host
+	}
+
+	if uploader != nil && (refresh || !isStoredObject(ctx, postImageURL)) {
+		updates := make(map[string]interface{})
+
+		var captured *companyImages
+		if website != "" {
+			if screenshotter, err := newScreenshotter(); err != nil {
+				log.Printf("WARNING: Screenshot provider not available: %v\n", err)
+			} else {
+				captured = captureCompanyImages(ctx, uploader, screenshotter, website, info)
+			}
+		}
+
+		if captured != nil {
+			cardURLs = captured.CardURLs
+			updates["cover_image"] = captured.CoverURL
+			if len(captured.CoverImages) > 0 {
+				updates["cover_images"] = captured.CoverImages
+			}
+			for format, cardURL := range cardURLs {
+				updates[cardColumns[format]] = cardURL
+			}
+			postImageURL = captured.CoverURL
+			if cardURL, ok := cardURLs[imaging.CardPortrait.Name]; ok {
+				postImageURL = cardURL
+			}
+		} else if _, stored := storage.KeyFromURL(company.CoverImage); !stored && company.CoverImage != "" {
+			// Screenshot failed; copy the external cover image into storage instead
+			if mirrored := mirrorCoverImage(ctx, uploader, company.CoverImage); mirrored != "" {
+				updates["cover_image"] = mirrored
+				postImageURL = mirrored
+			}
+		}
+
+		if len(updates) > 0 {
+			images.Refreshed = true
+			updates["updated_at"] = time.Now().UTC()
+			if err := database.NewCompanyRepository().Update(company.ID, updates); err != nil {
+				log.Printf("WARNING: Failed to save refreshed images for company %s: %v\n", company.ID, err)
+			}
+		}
+	}
+
+	if uploader != nil {
+		images.CarouselURLs = buildCarousel(ctx, uploader, cardURLs, company.Appeal, info)
+		linkCompanyAssets(company.ID, uploader.Assets())
+	}
+
+	images.PostImageURL = postImageURL
+	return images
+}
+
+// isStoredObject reports whether rawURL points at an object present in our storage
+func isStoredObject(ctx context.Context, rawURL string) bool {
+	key, ok := storage.KeyFromURL(rawURL)
+	store := storage.GetStore()
+	if !ok || store == nil {
+		return false
+	}
+	exists, err := store.Exists(ctx, key)
+	if err != nil {
+		log.Printf("WARNING: Failed to check stored image %s: %v\n", key, err)
+		// Assume it's there rather than re-capturing on a transient error
+		return true
+	}
+	return exists
+}
@@ -0,0 +1,227 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"startupdose.com/cmd/server/database"
+	"startupdose.com/cmd/server/models"
+)
+
+// Leaderboard size limits
+const (
+	defaultLeaderboardLimit = 10
+	maxLeaderboardLimit     = 100
+)
+
+// PostStats is a social post with its metrics over time
+type PostStats struct {
+	Channel   string     `json:"channel"`
+	Status    string     `json:"status"`
+	MediaID   *string    `json:"media_id"`
+	Permalink *string    `json:"permalink"`
+	PostedAt  *time.Time `json:"posted_at"`
+	// Latest is the most recent snapshot, nil until metrics were collected
+	Latest *models.SocialPostInsight `json:"latest"`
+	// Series holds every snapshot, oldest first
+	Series []models.SocialPostInsight `json:"series"`
+}
+
+// CompanyStatsResponse represents the response from the company stats endpoint
+type CompanyStatsResponse struct {
+	CompanyID   string      `json:"company_id"`
+	CompanyName string      `json:"company_name"`
+	CompanySlug string      `json:"company_slug"`
+	Posts       []PostStats `json:"posts"`
+}
+
+// LeaderboardResponse represents the response from the leaderboard endpoint
+type LeaderboardResponse struct {
+	Metric  string                    `json:"metric"`
+	Entries []models.LeaderboardEntry `json:"entries"`
+}
+
+// CompanyStatsHandler handles GET /companies/{id}/stats
+// Returns the Instagram metrics collected for each of the company's posts
+func CompanyStatsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// A malformed id can't match any company
+	id, ok := parseUUID(r.PathValue("id"))
+	if !ok {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(ErrorResponse{
+			Error:   "not_found",
+			Message: "company not found",
+		})
+		return
+	}
+
+	company, err := database.NewCompanyRepository().GetByID(id)
+	if err != nil {
+		if strings.Contains(err.Error(), "company not found") {
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(ErrorResponse{
+				Error:   "not_found",
+				Message: "company not found",
+			})
+			return
+		}
+
+		log.Printf("ERROR: Failed to retrieve company %q: %v\n", id, err)
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(ErrorResponse{
+			Error:   "internal_server_error",
+			Message: "Failed to retrieve company",
+		})
+		return
+	}
+
+	posts, err := database.NewSocialPostRepository().ListByCompany(company.ID)
+	if err != nil {
+		log.Printf("ERROR: Failed to load social posts for company %s: %v\n", company.ID, err)
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(ErrorResponse{
+			Error:   "internal_server_error",
+			Message: "Failed to load social posts",
+		})
+		return
+	}
+
+	insights, err := database.NewSocialPostInsightRepository().ListByCompany(company.ID)
+	if err != nil {
+		log.Printf("ERROR: Failed to load insights for company %s: %v\n", company.ID, err)
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(ErrorResponse{
+			Error:   "internal_server_error",
+			Message: "Failed to load insights",
+		})
+		return
+	}
+
+	// Snapshots arrive oldest first, so each series stays in order
+	series := make(map[string][]models.SocialPostInsight)
+	for _, insight := range insights {
+		series[insight.SocialPostID] = append(series[insight.SocialPostID], insight)
+	}
+
+	response := CompanyStatsResponse{
+		CompanyID:   company.ID,
+		CompanyName: company.Name,
+		CompanySlug: company.Slug,
+		Posts:       make([]PostStats, 0, len(posts)),
+	}
+	for _, post := range posts {
+		stats := PostStats{
+			Channel:   post.Channel,
+			Status:    post.Status,
+			MediaID:   post.MediaID,
+			Permalink: post.Permalink,
+			PostedAt:  post.PostedAt,
+			Series:    series[post.ID],
+		}
+		if stats.Series == nil {
+			stats.Series = []models.SocialPostInsight{}
+		}
+		if n := len(stats.Series); n > 0 {
+			stats.Latest = &stats.Series[n-1]
+		}
+		response.Posts = append(response.Posts, stats)
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// LeaderboardHandler handles GET /companies/leaderboard
+// Ranks posted companies by the latest value of a metric
+// ?metric= one of engagement (default), reach, impressions, likes, comments or saves
+// ?limit= number of entries, default 10, at most 100
+// ?days= only posts published within this many days
+func LeaderboardHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	metric := query.Get("metric")
+	if metric == "" {
+		metric = database.LeaderboardMetrics[0]
+	}
+	if !slices.Contains(database.LeaderboardMetrics, metric) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(ErrorResponse{
+			Error:   "bad_request",
+			Message: "metric must be one of " + strings.Join(database.LeaderboardMetrics, ", "),
+		})
+		return
+	}
+
+	limit := defaultLeaderboardLimit
+	if v := query.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxLeaderboardLimit {
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(ErrorResponse{
+				Error:   "bad_request",
+				Message: "limit must be between 1 and " + strconv.Itoa(maxLeaderboardLimit),
+			})
+			return
+		}
+		limit = n
+	}
+
+	var since time.Time
+	if v := query.Get("days"); v != "" {
+		days, err := strconv.Atoi(v)
+		if err != nil || days < 1 {
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(ErrorResponse{
+				Error:   "bad_request",
+				Message: "days must be a positive number",
+			})
+			return
+		}
+		since = time.Now().AddDate(0, 0, -days)
+	}
+
+	entries, err := database.NewSocialPostInsightRepository().Leaderboard(metric, since, limit)
+	if err != nil {
+		log.Printf("ERROR: Failed to load leaderboard: %v\n", err)
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(ErrorResponse{
+			Error:   "internal_server_error",
+			Message: "Failed to load leaderboard",
+		})
+		return
+	}
+	if entries == nil {
+		entries = []models.LeaderboardEntry{}
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(LeaderboardResponse{
+		Metric:  metric,
+		Entries: entries,
+	})
+}
@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+
+	"startupdose.com/cmd/server/budget"
+	"startupdose.com/cmd/server/database"
+	"startupdose.com/cmd/server/models"
+)
+
+// LLM usage purposes recorded alongside each call
+const (
+	usagePurposeCompanyGeneration = "company_generation"
+)
+
+// newSpendTracker builds a budget tracker from the LLM_* environment variables
+func newSpendTracker() *budget.Tracker {
+	return budget.NewTracker(database.NewLLMUsageRepository(), budget.Limits{
+		DailyUSD:   parseBudgetEnv("LLM_DAILY_BUDGET_USD"),
+		MonthlyUSD: parseBudgetEnv("LLM_MONTHLY_BUDGET_USD"),
+	})
+}
+
+// llmBudgetFailOpen reports whether generation goes ahead when spend can't be
+// computed, set with LLM_BUDGET_FAIL_OPEN=true
+func llmBudgetFailOpen() bool {
+	return os.Getenv("LLM_BUDGET_FAIL_OPEN") == "true"
+}
+
+// parseBudgetEnv reads a USD amount from the environment, returning 0 (no cap) when unset or invalid
+func parseBudgetEnv(key string) float64 {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return 0
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil || value < 0 {
+		log.Printf("WARNING: Invalid %s value %q, ignoring cap\n", key, raw)
+		return 0
+	}
+	return value
+}
+
+// Token counts assumed when estimating the cost of a call before making it
+const (
+	// charsPerToken is a conservative average for English prompts
+	charsPerToken = 3
+	// estimatedCompletionTokens is above the size of a typical generated company
+	estimatedCompletionTokens = 2000
+)
+
+// loadPriceTable reads LLM_PRICE_TABLE, falling back to the default prices
+func loadPriceTable() budget.PriceTable {
+	prices, err := budget.ParsePriceTable(os.Getenv("LLM_PRICE_TABLE"))
+	if err != nil {
+		log.Printf("WARNING: %v, using default prices\n", err)
+		return budget.DefaultPrices
+	}
+	return prices
+}
+
+// estimateLLMCost returns the expected USD cost of calls prompting model with prompt
+func estimateLLMCost(model, prompt string, calls int) float64 {
+	promptTokens := len(prompt)/charsPerToken + 1
+	cost, ok := loadPriceTable().Cost(model, promptTokens, estimatedCompletionTokens)
+	if !ok {
+		log.Printf("WARNING: No price configured for model %s, estimating zero cost\n", model)
+	}
+	return cost * float64(calls)
+}
+
+// recordLLMUsage computes the cost of a call and stores it
+// Failures are logged but never fail the request
+func recordLLMUsage(model, purpose string, usage OpenAIUsage) {
+	cost, ok := loadPriceTable().Cost(model, usage.PromptTokens, usage.CompletionTokens)
+	if !ok {
+		log.Printf("WARNING: No price configured for model %s, recording zero cost\n", model)
+	}
+
+	log.Printf("LLM usage: model=%s prompt_tokens=%d completion_tokens=%d cost_usd=%.6f\n",
+		model, usage.PromptTokens, usage.CompletionTokens, cost)
+
+	repo := database.NewLLMUsageRepository()
+	if err := repo.Insert(&models.LLMUsage{
+		Model:            model,
+		Purpose:          purpose,
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		TotalTokens:      usage.TotalTokens,
+		CostUSD:          cost,
+	}); err != nil {
+		log.Printf("ERROR: Failed to record LLM usage: %v\n", err)
+	}
+}
+
+// LLMSpendHandler handles GET /admin/llm/spend
+// Returns current daily and monthly LLM spend against the configured caps
+func LLMSpendHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	status, err := newSpendTracker().Status()
+	if err != nil {
+		log.Printf("ERROR: Failed to compute LLM spend: %v\n", err)
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(ErrorResponse{
+			Error:   "internal_server_error",
+			Message: "Failed to compute LLM spend",
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(status)
+}
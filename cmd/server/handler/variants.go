@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"startupdose.com/cmd/server/imaging"
+	"startupdose.com/cmd/server/models"
+	"startupdose.com/cmd/server/storage"
+)
+
+// variantWidthsFromEnv parses IMAGE_VARIANT_WIDTHS ("320,640,1280")
+func variantWidthsFromEnv() []int {
+	raw := os.Getenv("IMAGE_VARIANT_WIDTHS")
+	if raw == "" {
+		return imaging.DefaultVariantWidths
+	}
+
+	var widths []int
+	for _, part := range strings.Split(raw, ",") {
+		width, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || width <= 0 {
+			log.Printf("WARNING: Ignoring invalid IMAGE_VARIANT_WIDTHS entry %q\n", part)
+			continue
+		}
+		widths = append(widths, width)
+	}
+	return widths
+}
+
+// uploadCoverImageVariants generates responsive JPEG/WebP variants of an uploaded
+// image and returns the full set of renditions, starting with the original
+// Variants that fail are logged and skipped
+func uploadCoverImageVariants(ctx context.Context, uploader *storage.Uploader, original []byte, originalURL string) []models.CoverImage {
+	var images []models.CoverImage
+
+	if cfg, format, err := image.DecodeConfig(bytes.NewReader(original)); err == nil {
+		images = append(images, models.CoverImage{
+			URL:    originalURL,
+			Width:  cfg.Width,
+			Height: cfg.Height,
+			Format: format,
+		})
+	} else {
+		log.Printf("WARNING: Failed to read original image size: %v\n", err)
+	}
+
+	encoder := imaging.NewVariantEncoder(variantWidthsFromEnv(), os.Getenv("CWEBP_PATH"))
+	variants, err := encoder.Generate(original)
+	if err != nil {
+		log.Printf("ERROR: Failed to generate image variants: %v\n", err)
+		return images
+	}
+
+	for _, v := range variants {
+		asset, err := uploader.UploadVariant(ctx, v.Data, v.ContentType, v.Extension())
+		if err != nil {
+			log.Printf("ERROR: Failed to upload %dw %s variant: %v\n", v.Width, v.Format, err)
+			continue
+		}
+		images = append(images, models.CoverImage{
+			URL:    asset.URL,
+			Width:  v.Width,
+			Height: v.Height,
+			Format: v.Format,
+		})
+	}
+
+	log.Printf("Uploaded %d cover image renditions\n", len(images))
+	return images
+}
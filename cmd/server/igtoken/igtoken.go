@@ -0,0 +1,396 @@
+// Package igtoken keeps the Instagram access token alive. Long-lived tokens
+// expire after 60 days; the Manager stores the current token in the database,
+// inspects it periodically, refreshes it before it expires and raises an alert
+// when it cannot.
+package igtoken
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"startupdose.com/cmd/server/config"
+	"startupdose.com/cmd/server/database"
+	"startupdose.com/cmd/server/instagram"
+	"startupdose.com/cmd/server/models"
+	"startupdose.com/cmd/server/notify"
+)
+
+// Defaults used when Options leaves a field zero
+const (
+	DefaultRefreshBefore = 10 * 24 * time.Hour
+	DefaultCheckInterval = 12 * time.Hour
+)
+
+// failedCheckInterval is how soon a failed check is retried
+const failedCheckInterval = time.Hour
+
+// Store persists the current token
+type Store interface {
+	// Get returns the stored token, or nil if none is stored
+	Get(igUserID string) (*models.InstagramToken, error)
+	Save(token *models.InstagramToken) error
+}
+
+// Notifier delivers alerts
+type Notifier interface {
+	Notify(ctx context.Context, message string) error
+}
+
+// Options configures a Manager
+type Options struct {
+	UserID string
+	// SeedToken is the IG_ACCESS_TOKEN value, used until a token is stored
+	// and adopted when the stored one has become invalid
+	SeedToken  string
+	AppID      string
+	AppSecret  string
+	APIVersion string
+	// RefreshBefore is how long before expiry the token is refreshed
+	RefreshBefore time.Duration
+	// CheckInterval is how often the token is inspected
+	CheckInterval time.Duration
+}
+
+// Status describes the current token without revealing it
+type Status struct {
+	Configured          bool       `json:"configured"`
+	Valid               bool       `json:"valid"`
+	ExpiresAt           *time.Time `json:"expires_at,omitempty"`
+	DataAccessExpiresAt *time.Time `json:"data_access_expires_at,omitempty"`
+	LastRefreshedAt     *time.Time `json:"last_refreshed_at,omitempty"`
+	LastCheckedAt       *time.Time `json:"last_checked_at,omitempty"`
+	LastError           string     `json:"last_error,omitempty"`
+	Healthy             bool       `json:"healthy"`
+	// Reason explains why the token is unhealthy
+	Reason string `json:"reason,omitempty"`
+}
+
+// Manager tracks and refreshes the Instagram access token
+type Manager struct {
+	opts     Options
+	store    Store
+	notifier Notifier
+	now      func() time.Time
+
+	mu    sync.RWMutex
+	token *models.InstagramToken
+	// alerted is the reason last notified, so each failure is reported once
+	alerted string
+}
+
+// NewManager creates a new Manager
+func NewManager(store Store, notifier Notifier, opts Options) *Manager {
+	if opts.RefreshBefore <= 0 {
+		opts.RefreshBefore = DefaultRefreshBefore
+	}
+	if opts.CheckInterval <= 0 {
+		opts.CheckInterval = DefaultCheckInterval
+	}
+	return &Manager{
+		opts:     opts,
+		store:    store,
+		notifier: notifier,
+		now:      time.Now,
+	}
+}
+
+var (
+	manager     *Manager
+	managerOnce sync.Once
+)
+
+// Init creates the process-wide Manager from cfg
+func Init(cfg *config.Config) *Manager {
+	managerOnce.Do(func() {
+		refreshBefore, err := time.ParseDuration(cfg.IGTokenRefreshBefore)
+		if err != nil {
+			log.Printf("WARNING: Invalid IG_TOKEN_REFRESH_BEFORE %q, using %s\n", cfg.IGTokenRefreshBefore, DefaultRefreshBefore)
+		}
+		checkInterval, err := time.ParseDuration(cfg.IGTokenCheckInterval)
+		if err != nil {
+			log.Printf("WARNING: Invalid IG_TOKEN_CHECK_INTERVAL %q, using %s\n", cfg.IGTokenCheckInterval, DefaultCheckInterval)
+		}
+
+		manager = NewManager(database.NewInstagramTokenRepository(), notify.New(cfg.NotifyWebhookURL), Options{
+			UserID:        cfg.IGUserID,
+			SeedToken:     cfg.IGAccessToken,
+			AppID:         cfg.IGAppID,
+			AppSecret:     cfg.IGAppSecret,
+			APIVersion:    cfg.IGAPIVersion,
+			RefreshBefore: refreshBefore,
+			CheckInterval: checkInterval,
+		})
+	})
+	return manager
+}
+
+// Get returns the process-wide Manager, or nil before Init
+func Get() *Manager {
+	return manager
+}
+
+// AccessToken returns the current Instagram access token, or "" if none
+func AccessToken() string {
+	if manager == nil {
+		return ""
+	}
+	return manager.Token()
+}
+
+// Token returns the current access token, falling back to the seed token
+// until the first check has run
+func (m *Manager) Token() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.token != nil {
+		return m.token.AccessToken
+	}
+	return m.opts.SeedToken
+}
+
+// Run checks the token immediately and then every CheckInterval until ctx is done
+// Failed checks are retried sooner
+func (m *Manager) Run(ctx context.Context) {
+	if m.opts.UserID == "" {
+		log.Println("INFO: Instagram token monitoring disabled, IG_USER_ID not set")
+		return
+	}
+
+	for {
+		next := m.opts.CheckInterval
+		if err := m.Check(ctx); err != nil {
+			log.Printf("ERROR: Instagram token check failed: %v\n", err)
+			if next > failedCheckInterval {
+				next = failedCheckInterval
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(next):
+		}
+	}
+}
+
+// Check loads the stored token, inspects it with the Graph API, refreshes it
+// when it expires within RefreshBefore and saves the outcome
+// Alerts are sent when the token becomes unhealthy and when it recovers
+func (m *Manager) Check(ctx context.Context) error {
+	token, err := m.load()
+	if err != nil {
+		return err
+	}
+
+	now := m.now().UTC()
+	token.LastCheckedAt = &now
+	token.LastError = nil
+	checkErr := m.inspect(ctx, token)
+	// A failed inspection keeps the stored expiry, so refreshing still happens on time
+	if token.IsValid && m.needsRefresh(token, now) {
+		if err := m.refresh(ctx, token, now); err != nil {
+			checkErr = err
+		}
+	}
+	if checkErr != nil {
+		msg := checkErr.Error()
+		token.LastError = &msg
+	}
+
+	m.mu.Lock()
+	m.token = token
+	m.mu.Unlock()
+
+	if err := m.store.Save(token); err != nil {
+		log.Printf("WARNING: Failed to save Instagram token: %v\n", err)
+	}
+
+	m.alert(ctx)
+	return checkErr
+}
+
+// load returns a copy of the stored token, seeding it from IG_ACCESS_TOKEN
+// If the database is unavailable the in-memory token is used
+func (m *Manager) load() (*models.InstagramToken, error) {
+	stored, err := m.store.Get(m.opts.UserID)
+	if err != nil {
+		log.Printf("WARNING: Failed to load Instagram token, using in-memory token: %v\n", err)
+		m.mu.RLock()
+		stored = m.token
+		m.mu.RUnlock()
+	}
+	if stored != nil {
+		token := *stored
+		return &token, nil
+	}
+
+	if m.opts.SeedToken == "" {
+		return nil, fmt.Errorf("no Instagram access token stored or configured")
+	}
+	log.Println("INFO: Seeding stored Instagram token from IG_ACCESS_TOKEN")
+	return &models.InstagramToken{
+		IGUserID:    m.opts.UserID,
+		AccessToken: m.opts.SeedToken,
+		IsValid:     true,
+	}, nil
+}
+
+// inspect updates validity, expiry and scopes from the debug_token endpoint
+// An invalid stored token is replaced by IG_ACCESS_TOKEN when that differs and
+// is valid, so operators can recover by setting a new token in the environment
+func (m *Manager) inspect(ctx context.Context, token *models.InstagramToken) error {
+	info, err := m.debug(ctx, token.AccessToken)
+	if err != nil {
+		return fmt.Errorf("failed to inspect token: %w", err)
+	}
+
+	if !info.Valid && m.opts.SeedToken != "" && m.opts.SeedToken != token.AccessToken {
+		if seedInfo, err := m.debug(ctx, m.opts.SeedToken); err == nil && seedInfo.Valid {
+			log.Println("INFO: Stored Instagram token is invalid, switching to IG_ACCESS_TOKEN")
+			token.AccessToken = m.opts.SeedToken
+			info = seedInfo
+		}
+	}
+
+	token.IsValid = info.Valid
+	token.ExpiresAt = timePtr(info.ExpiresAt)
+	token.DataAccessExpiresAt = timePtr(info.DataAccessExpiresAt)
+	token.Scopes = info.Scopes
+
+	if !info.Valid {
+		if info.Error != "" {
+			return fmt.Errorf("token is invalid: %s", info.Error)
+		}
+		return fmt.Errorf("token is invalid")
+	}
+	return nil
+}
+
+// debug inspects accessToken, with the app token when credentials are set
+func (m *Manager) debug(ctx context.Context, accessToken string) (*instagram.TokenInfo, error) {
+	inspector := accessToken
+	if m.hasAppCredentials() {
+		inspector = instagram.AppAccessToken(m.opts.AppID, m.opts.AppSecret)
+	}
+	return instagram.NewClient(m.opts.UserID, accessToken, m.opts.APIVersion).DebugToken(ctx, inspector)
+}
+
+// needsRefresh reports whether the token expires within RefreshBefore
+// Tokens without an expiry never need refreshing
+func (m *Manager) needsRefresh(token *models.InstagramToken, now time.Time) bool {
+	return token.ExpiresAt != nil && token.ExpiresAt.Sub(now) < m.opts.RefreshBefore
+}
+
+// refresh exchanges the token for a new long-lived one
+func (m *Manager) refresh(ctx context.Context, token *models.InstagramToken, now time.Time) error {
+	if !m.hasAppCredentials() {
+		return fmt.Errorf("token expires %s but IG_APP_ID and IG_APP_SECRET are not set, cannot refresh", token.ExpiresAt.Format(time.RFC3339))
+	}
+
+	client := instagram.NewClient(m.opts.UserID, token.AccessToken, m.opts.APIVersion)
+	refreshed, err := client.RefreshToken(ctx, m.opts.AppID, m.opts.AppSecret)
+	if err != nil {
+		return fmt.Errorf("failed to refresh token: %w", err)
+	}
+
+	token.AccessToken = refreshed.AccessToken
+	token.LastRefreshedAt = &now
+	if !refreshed.ExpiresAt.IsZero() {
+		expiresAt := refreshed.ExpiresAt.UTC()
+		token.ExpiresAt = &expiresAt
+	}
+	log.Printf("INFO: Refreshed Instagram token, now expires %s\n", formatTime(token.ExpiresAt))
+	return nil
+}
+
+// hasAppCredentials reports whether the app ID and secret are configured
+func (m *Manager) hasAppCredentials() bool {
+	return m.opts.AppID != "" && m.opts.AppSecret != ""
+}
+
+// Status returns the health of the current token
+func (m *Manager) Status() Status {
+	m.mu.RLock()
+	token := m.token
+	m.mu.RUnlock()
+
+	status := Status{Configured: m.opts.UserID != "" && (token != nil || m.opts.SeedToken != "")}
+	if token == nil {
+		status.Valid = status.Configured
+		status.Healthy = status.Configured
+		if !status.Configured {
+			status.Reason = "Instagram access token not configured"
+		}
+		return status
+	}
+
+	status.Valid = token.IsValid
+	status.ExpiresAt = token.ExpiresAt
+	status.DataAccessExpiresAt = token.DataAccessExpiresAt
+	status.LastRefreshedAt = token.LastRefreshedAt
+	status.LastCheckedAt = token.LastCheckedAt
+	if token.LastError != nil {
+		status.LastError = *token.LastError
+	}
+
+	now := m.now()
+	switch {
+	case token.ExpiresAt != nil && now.After(*token.ExpiresAt):
+		status.Reason = fmt.Sprintf("token expired at %s", formatTime(token.ExpiresAt))
+	case !token.IsValid:
+		status.Reason = "token is invalid"
+	case status.LastError != "":
+		status.Reason = status.LastError
+	case token.DataAccessExpiresAt != nil && token.DataAccessExpiresAt.Sub(now) < m.opts.RefreshBefore:
+		status.Reason = fmt.Sprintf("data access expires at %s, log in again to renew it", formatTime(token.DataAccessExpiresAt))
+	}
+	status.Healthy = status.Reason == ""
+	return status
+}
+
+// alert notifies when the token becomes unhealthy, when the reason changes and
+// when it recovers
+func (m *Manager) alert(ctx context.Context) {
+	status := m.Status()
+
+	m.mu.Lock()
+	previous := m.alerted
+	m.alerted = status.Reason
+	m.mu.Unlock()
+
+	var message string
+	switch {
+	case status.Reason != "" && status.Reason != previous:
+		message = "Instagram token problem: " + status.Reason
+	case status.Reason == "" && previous != "":
+		message = "Instagram token recovered"
+	default:
+		return
+	}
+	if status.ExpiresAt != nil {
+		message += fmt.Sprintf(" (expires %s)", formatTime(status.ExpiresAt))
+	}
+
+	if err := m.notifier.Notify(ctx, message); err != nil {
+		log.Printf("WARNING: Failed to send notification: %v\n", err)
+	}
+}
+
+// timePtr returns nil for the zero time
+func timePtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// formatTime formats an optional expiry for messages
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return "never"
+	}
+	return t.UTC().Format(time.RFC3339)
+}
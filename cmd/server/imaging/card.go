@@ -0,0 +1,205 @@
+package imaging
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/jpeg" // Screenshots may be captured as JPEG
+	"image/png"
+
+	xdraw "golang.org/x/image/draw"
+)
+
+// CardFormat is the output size of a composed card
+type CardFormat struct {
+	Name   string
+	Width  int
+	Height int
+}
+
+// Card formats accepted by Instagram feed posts without cropping
+var (
+	CardSquare   = CardFormat{Name: "square", Width: 1080, Height: 1080}
+	CardPortrait = CardFormat{Name: "portrait", Width: 1080, Height: 1350}
+)
+
+// CardStory fills the full 9:16 screen used by stories and reels
+var CardStory = CardFormat{Name: "story", Width: 1080, Height: 1920}
+
+// CardFormats lists the formats rendered for every company
+var CardFormats = []CardFormat{CardSquare, CardPortrait}
+
+// CardInfo is the company data printed on a card
+type CardInfo struct {
+	CompanyName string
+	// Domain is shown in the browser mock's address bar
+	Domain string
+}
+
+// Brand colors
+var (
+	backgroundTop    = color.RGBA{0x0B, 0x15, 0x30, 0xFF}
+	backgroundBottom = color.RGBA{0x1E, 0x2A, 0x5A, 0xFF}
+	accent           = color.RGBA{0xFF, 0x6B, 0x35, 0xFF}
+	white            = color.RGBA{0xFF, 0xFF, 0xFF, 0xFF}
+	chromeGray       = color.RGBA{0xE5, 0xE7, 0xEB, 0xFF}
+	addressGray      = color.RGBA{0x6B, 0x72, 0x80, 0xFF}
+	dotRed           = color.RGBA{0xFF, 0x5F, 0x57, 0xFF}
+	dotYellow        = color.RGBA{0xFE, 0xBC, 0x2E, 0xFF}
+	dotGreen         = color.RGBA{0x28, 0xC8, 0x40, 0xFF}
+)
+
+// Layout constants in pixels
+const (
+	cardMargin      = 64
+	bannerHeight    = 72
+	titleBarHeight  = 56
+	frameRadius     = 24
+	sectionSpacing  = 48
+	logoHeight      = 40
+	nameMaxFontSize = 80
+	nameMinFontSize = 40
+)
+
+// ComposeCard renders a branded card with the screenshot framed in a browser mock,
+// the "Today's Fix" banner, the company name and the Startup Dose logo
+// Returns PNG bytes
+func ComposeCard(screenshot []byte, info CardInfo, format CardFormat) ([]byte, error) {
+	if err := loadFonts(); err != nil {
+		return nil, err
+	}
+
+	shot, _, err := image.Decode(bytes.NewReader(screenshot))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode screenshot: %w", err)
+	}
+
+	w, h := format.Width, format.Height
+	canvas := image.NewRGBA(image.Rect(0, 0, w, h))
+	fillVerticalGradient(canvas, backgroundTop, backgroundBottom)
+
+	// Banner
+	if err := drawBanner(canvas, "TODAY'S FIX", cardMargin); err != nil {
+		return nil, err
+	}
+
+	// Logo along the bottom edge
+	logoBaseline := h - cardMargin
+	if err := drawLogo(canvas, w/2, logoBaseline); err != nil {
+		return nil, err
+	}
+
+	// Company name above the logo
+	nameFace, name, err := fitFace(boldFont, info.CompanyName, w-2*cardMargin, nameMaxFontSize, nameMinFontSize)
+	if err != nil {
+		return nil, err
+	}
+	defer nameFace.Close()
+	nameBaseline := logoBaseline - logoHeight - sectionSpacing
+	drawTextCentered(canvas, nameFace, name, w/2, nameBaseline, white)
+	nameTop := nameBaseline - nameFace.Metrics().Ascent.Ceil()
+
+	// Browser mock fills the space between banner and name
+	areaTop := cardMargin + bannerHeight + sectionSpacing
+	areaBottom := nameTop - sectionSpacing
+	if err := drawBrowserFrame(canvas, shot, info.Domain, image.Rect(cardMargin, areaTop, w-cardMargin, areaBottom)); err != nil {
+		return nil, err
+	}
+
+	return encodeCard(canvas)
+}
+
+// encodeCard encodes a finished card as PNG
+func encodeCard(canvas *image.RGBA) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, canvas); err != nil {
+		return nil, fmt.Errorf("failed to encode card: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// drawBanner draws a centered accent pill with text at the given top offset
+func drawBanner(dst *image.RGBA, text string, top int) error {
+	face, err := newFace(boldFont, 40)
+	if err != nil {
+		return err
+	}
+	defer face.Close()
+
+	width := measure(face, text) + 80
+	cx := dst.Bounds().Dx() / 2
+	rect := image.Rect(cx-width/2, top, cx+width/2, top+bannerHeight)
+	fillRoundedRect(dst, rect, bannerHeight/2, accent)
+
+	baseline := top + bannerHeight/2 + face.Metrics().CapHeight.Ceil()/2
+	drawTextCentered(dst, face, text, cx, baseline, white)
+	return nil
+}
+
+// drawLogo draws the capsule mark and "Startup Dose" wordmark centered on cx
+func drawLogo(dst *image.RGBA, cx, baseline int) error {
+	face, err := newFace(boldFont, 32)
+	if err != nil {
+		return err
+	}
+	defer face.Close()
+
+	const capsuleWidth, capsuleHeight, gap = 64, 28, 16
+	text := "Startup Dose"
+	total := capsuleWidth + gap + measure(face, text)
+	left := cx - total/2
+
+	// Capsule: accent left half, white right half
+	capHeight := face.Metrics().CapHeight.Ceil()
+	top := baseline - capHeight/2 - capsuleHeight/2
+	capsule := image.Rect(left, top, left+capsuleWidth, top+capsuleHeight)
+	fillRoundedRect(dst, capsule, capsuleHeight/2, white)
+	leftHalf := image.Rect(capsule.Min.X, capsule.Min.Y, capsule.Min.X+capsuleWidth/2, capsule.Max.Y)
+	draw.DrawMask(dst, leftHalf, image.NewUniform(accent), image.Point{}, roundedRect{rect: capsule, radius: capsuleHeight / 2}, leftHalf.Min, draw.Over)
+
+	drawText(dst, face, text, left+capsuleWidth+gap, baseline, white)
+	return nil
+}
+
+// drawBrowserFrame draws the screenshot inside a browser window mock that fits area
+// The screenshot is scaled to the frame width and cropped at the bottom if too tall
+func drawBrowserFrame(dst *image.RGBA, shot image.Image, domain string, area image.Rectangle) error {
+	frameWidth := area.Dx()
+	sb := shot.Bounds()
+	shotHeight := frameWidth * sb.Dy() / sb.Dx()
+
+	frameHeight := titleBarHeight + shotHeight
+	if frameHeight > area.Dy() {
+		frameHeight = area.Dy()
+	}
+	top := area.Min.Y + (area.Dy()-frameHeight)/2
+	frame := image.Rect(area.Min.X, top, area.Max.X, top+frameHeight)
+	mask := roundedRect{rect: frame, radius: frameRadius}
+
+	// Title bar with window controls and address bar
+	fillRoundedRect(dst, frame, frameRadius, chromeGray)
+	dotY := frame.Min.Y + titleBarHeight/2
+	for i, c := range []color.RGBA{dotRed, dotYellow, dotGreen} {
+		fillCircle(dst, frame.Min.X+32+i*32, dotY, 10, c)
+	}
+	address := image.Rect(frame.Min.X+140, dotY-16, frame.Max.X-32, dotY+16)
+	fillRoundedRect(dst, address, 16, white)
+	if domain != "" {
+		face, err := newFace(regularFont, 22)
+		if err != nil {
+			return err
+		}
+		defer face.Close()
+		drawText(dst, face, domain, address.Min.X+20, dotY+face.Metrics().CapHeight.Ceil()/2, addressGray)
+	}
+
+	// Screenshot, scaled to the frame width and clipped to the rounded frame
+	scaled := image.NewRGBA(image.Rect(0, 0, frameWidth, shotHeight))
+	xdraw.CatmullRom.Scale(scaled, scaled.Bounds(), shot, sb, xdraw.Src, nil)
+	content := image.Rect(frame.Min.X, frame.Min.Y+titleBarHeight, frame.Max.X, frame.Max.Y)
+	draw.DrawMask(dst, content, scaled, image.Point{}, mask, content.Min, draw.Over)
+
+	return nil
+}
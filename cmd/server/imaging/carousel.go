@@ -0,0 +1,129 @@
+package imaging
+
+import (
+	"fmt"
+	"image"
+)
+
+// Carousel layout constants in pixels
+const (
+	badgeRadius       = 56
+	bulletMaxLines    = 7
+	bulletMaxFontSize = 72
+	bulletMinFontSize = 40
+	ctaLineSpacing    = 24
+)
+
+// ComposeBulletCard renders one appeal bullet as a carousel slide, numbered
+// index of total (1-based), with the company name and logo along the bottom
+// Returns PNG bytes
+func ComposeBulletCard(text string, index, total int, info CardInfo, format CardFormat) ([]byte, error) {
+	if err := loadFonts(); err != nil {
+		return nil, err
+	}
+
+	w, h := format.Width, format.Height
+	canvas := image.NewRGBA(image.Rect(0, 0, w, h))
+	fillVerticalGradient(canvas, backgroundTop, backgroundBottom)
+
+	if err := drawBanner(canvas, "WHY IT MATTERS", cardMargin); err != nil {
+		return nil, err
+	}
+
+	// Logo and company name along the bottom edge
+	logoBaseline := h - cardMargin
+	if err := drawLogo(canvas, w/2, logoBaseline); err != nil {
+		return nil, err
+	}
+	nameFace, name, err := fitFace(regularFont, info.CompanyName, w-2*cardMargin, 40, 28)
+	if err != nil {
+		return nil, err
+	}
+	defer nameFace.Close()
+	nameBaseline := logoBaseline - logoHeight - sectionSpacing
+	drawTextCentered(canvas, nameFace, name, w/2, nameBaseline, chromeGray)
+
+	// Numbered badge below the banner
+	badgeCenterY := cardMargin + bannerHeight + sectionSpacing + badgeRadius
+	fillCircle(canvas, w/2, badgeCenterY, badgeRadius, accent)
+	badgeFace, err := newFace(boldFont, 44)
+	if err != nil {
+		return nil, err
+	}
+	defer badgeFace.Close()
+	badgeText := fmt.Sprintf("%d/%d", index, total)
+	drawTextCentered(canvas, badgeFace, badgeText, w/2, badgeCenterY+badgeFace.Metrics().CapHeight.Ceil()/2, white)
+
+	// Bullet text, wrapped and centered in the remaining space
+	textFace, lines, err := fitWrappedFace(boldFont, text, w-2*cardMargin, bulletMaxLines, bulletMaxFontSize, bulletMinFontSize)
+	if err != nil {
+		return nil, err
+	}
+	defer textFace.Close()
+	areaTop := badgeCenterY + badgeRadius + sectionSpacing
+	areaBottom := nameBaseline - nameFace.Metrics().Ascent.Ceil() - sectionSpacing
+	drawLinesCentered(canvas, textFace, lines, w/2, areaTop, areaBottom, white)
+
+	return encodeCard(canvas)
+}
+
+// ComposeCTACard renders the closing carousel slide pointing readers to the
+// company's website and inviting them to follow for the next pick
+// Returns PNG bytes
+func ComposeCTACard(info CardInfo, format CardFormat) ([]byte, error) {
+	if err := loadFonts(); err != nil {
+		return nil, err
+	}
+
+	w, h := format.Width, format.Height
+	canvas := image.NewRGBA(image.Rect(0, 0, w, h))
+	fillVerticalGradient(canvas, backgroundTop, backgroundBottom)
+
+	if err := drawBanner(canvas, "CHECK IT OUT", cardMargin); err != nil {
+		return nil, err
+	}
+
+	logoBaseline := h - cardMargin
+	if err := drawLogo(canvas, w/2, logoBaseline); err != nil {
+		return nil, err
+	}
+
+	nameFace, name, err := fitFace(boldFont, info.CompanyName, w-2*cardMargin, nameMaxFontSize, nameMinFontSize)
+	if err != nil {
+		return nil, err
+	}
+	defer nameFace.Close()
+
+	bodyFace, err := newFace(regularFont, 44)
+	if err != nil {
+		return nil, err
+	}
+	defer bodyFace.Close()
+
+	domain := info.Domain
+	if domain == "" {
+		domain = "the link in our bio"
+	}
+	domainFace, domainText, err := fitFace(boldFont, domain, w-2*cardMargin, 56, 32)
+	if err != nil {
+		return nil, err
+	}
+	defer domainFace.Close()
+
+	// Name, domain and follow prompt stacked around the vertical center
+	nameHeight := nameFace.Metrics().Height.Ceil()
+	domainHeight := domainFace.Metrics().Height.Ceil()
+	bodyHeight := bodyFace.Metrics().Height.Ceil()
+	blockHeight := nameHeight + sectionSpacing + bodyHeight + ctaLineSpacing + domainHeight + 2*sectionSpacing + bodyHeight
+	y := (h-blockHeight)/2 + nameFace.Metrics().Ascent.Ceil()
+
+	drawTextCentered(canvas, nameFace, name, w/2, y, white)
+	y += nameFace.Metrics().Descent.Ceil() + sectionSpacing + bodyFace.Metrics().Ascent.Ceil()
+	drawTextCentered(canvas, bodyFace, "Learn more at", w/2, y, chromeGray)
+	y += bodyFace.Metrics().Descent.Ceil() + ctaLineSpacing + domainFace.Metrics().Ascent.Ceil()
+	drawTextCentered(canvas, domainFace, domainText, w/2, y, accent)
+	y += domainFace.Metrics().Descent.Ceil() + 2*sectionSpacing + bodyFace.Metrics().Ascent.Ceil()
+	drawTextCentered(canvas, bodyFace, "Follow for a new startup every day", w/2, y, white)
+
+	return encodeCard(canvas)
+}
@@ -0,0 +1,224 @@
+package imaging
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"strings"
+	"sync"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// Embedded Go fonts, parsed once on first use
+var (
+	fontsOnce    sync.Once
+	regularFont  *opentype.Font
+	boldFont     *opentype.Font
+	fontsLoadErr error
+)
+
+// loadFonts parses the embedded Go fonts
+func loadFonts() error {
+	fontsOnce.Do(func() {
+		regularFont, fontsLoadErr = opentype.Parse(goregular.TTF)
+		if fontsLoadErr != nil {
+			return
+		}
+		boldFont, fontsLoadErr = opentype.Parse(gobold.TTF)
+	})
+	if fontsLoadErr != nil {
+		return fmt.Errorf("failed to load fonts: %w", fontsLoadErr)
+	}
+	return nil
+}
+
+// newFace creates a font face at the given pixel size
+func newFace(f *opentype.Font, size float64) (font.Face, error) {
+	face, err := opentype.NewFace(f, &opentype.FaceOptions{
+		Size:    size,
+		DPI:     72,
+		Hinting: font.HintingFull,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create font face: %w", err)
+	}
+	return face, nil
+}
+
+// fitFace returns the largest face between minSize and maxSize in which text
+// fits maxWidth, and the text itself, shortened with an ellipsis if needed
+func fitFace(f *opentype.Font, text string, maxWidth int, maxSize, minSize float64) (font.Face, string, error) {
+	for size := maxSize; size >= minSize; size -= 4 {
+		face, err := newFace(f, size)
+		if err != nil {
+			return nil, "", err
+		}
+		if measure(face, text) <= maxWidth {
+			return face, text, nil
+		}
+		face.Close()
+	}
+
+	face, err := newFace(f, minSize)
+	if err != nil {
+		return nil, "", err
+	}
+	runes := []rune(text)
+	for len(runes) > 1 && measure(face, string(runes)+"…") > maxWidth {
+		runes = runes[:len(runes)-1]
+	}
+	return face, string(runes) + "…", nil
+}
+
+// wrapText breaks text into lines no wider than maxWidth, splitting on spaces
+// Words longer than a line are kept whole and may overflow
+func wrapText(face font.Face, text string, maxWidth int) []string {
+	var lines []string
+	var line string
+	for _, word := range strings.Fields(text) {
+		candidate := word
+		if line != "" {
+			candidate = line + " " + word
+		}
+		if line != "" && measure(face, candidate) > maxWidth {
+			lines = append(lines, line)
+			line = word
+			continue
+		}
+		line = candidate
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// fitWrappedFace returns the largest face between minSize and maxSize at which
+// text wraps into at most maxLines lines of maxWidth, and the wrapped lines
+// At minSize, lines past maxLines are dropped and the last one gets an ellipsis
+func fitWrappedFace(f *opentype.Font, text string, maxWidth, maxLines int, maxSize, minSize float64) (font.Face, []string, error) {
+	for size := maxSize; size >= minSize; size -= 4 {
+		face, err := newFace(f, size)
+		if err != nil {
+			return nil, nil, err
+		}
+		if lines := wrapText(face, text, maxWidth); len(lines) <= maxLines {
+			return face, lines, nil
+		}
+		face.Close()
+	}
+
+	face, err := newFace(f, minSize)
+	if err != nil {
+		return nil, nil, err
+	}
+	lines := wrapText(face, text, maxWidth)
+	if len(lines) > maxLines {
+		lines = lines[:maxLines]
+		last := []rune(lines[maxLines-1])
+		for len(last) > 1 && measure(face, string(last)+"…") > maxWidth {
+			last = last[:len(last)-1]
+		}
+		lines[maxLines-1] = strings.TrimSpace(string(last)) + "…"
+	}
+	return face, lines, nil
+}
+
+// measure returns the rendered width of text in pixels
+func measure(face font.Face, text string) int {
+	return font.MeasureString(face, text).Ceil()
+}
+
+// drawText draws text with its baseline at (x, y)
+func drawText(dst draw.Image, face font.Face, text string, x, y int, c color.Color) {
+	d := &font.Drawer{
+		Dst:  dst,
+		Src:  image.NewUniform(c),
+		Face: face,
+		Dot:  fixed.P(x, y),
+	}
+	d.DrawString(text)
+}
+
+// drawTextCentered draws text horizontally centered on cx with its baseline at y
+func drawTextCentered(dst draw.Image, face font.Face, text string, cx, y int, c color.Color) {
+	drawText(dst, face, text, cx-measure(face, text)/2, y, c)
+}
+
+// drawLinesCentered draws lines horizontally centered on cx and vertically
+// centered between top and bottom
+func drawLinesCentered(dst draw.Image, face font.Face, lines []string, cx, top, bottom int, c color.Color) {
+	lineHeight := face.Metrics().Height.Ceil()
+	y := top + (bottom-top-lineHeight*len(lines))/2 + face.Metrics().Ascent.Ceil()
+	for _, line := range lines {
+		drawTextCentered(dst, face, line, cx, y, c)
+		y += lineHeight
+	}
+}
+
+// roundedRect is an alpha mask for a rectangle with rounded corners
+type roundedRect struct {
+	rect   image.Rectangle
+	radius int
+}
+
+func (r roundedRect) ColorModel() color.Model { return color.AlphaModel }
+func (r roundedRect) Bounds() image.Rectangle { return r.rect }
+
+func (r roundedRect) At(x, y int) color.Color {
+	if !(image.Point{x, y}).In(r.rect) {
+		return color.Transparent
+	}
+
+	// Distance from the nearest corner center, only relevant inside corner squares
+	cx, cy := x, y
+	switch {
+	case x < r.rect.Min.X+r.radius:
+		cx = r.rect.Min.X + r.radius
+	case x >= r.rect.Max.X-r.radius:
+		cx = r.rect.Max.X - r.radius - 1
+	}
+	switch {
+	case y < r.rect.Min.Y+r.radius:
+		cy = r.rect.Min.Y + r.radius
+	case y >= r.rect.Max.Y-r.radius:
+		cy = r.rect.Max.Y - r.radius - 1
+	}
+	dx, dy := x-cx, y-cy
+	if dx*dx+dy*dy > r.radius*r.radius {
+		return color.Transparent
+	}
+	return color.Opaque
+}
+
+// fillRoundedRect fills rect with c using rounded corners
+func fillRoundedRect(dst draw.Image, rect image.Rectangle, radius int, c color.Color) {
+	draw.DrawMask(dst, rect, image.NewUniform(c), image.Point{}, roundedRect{rect: rect, radius: radius}, rect.Min, draw.Over)
+}
+
+// fillCircle fills a circle centered at (cx, cy)
+func fillCircle(dst draw.Image, cx, cy, radius int, c color.Color) {
+	rect := image.Rect(cx-radius, cy-radius, cx+radius, cy+radius)
+	fillRoundedRect(dst, rect, radius, c)
+}
+
+// fillVerticalGradient fills dst from top to bottom
+func fillVerticalGradient(dst *image.RGBA, top, bottom color.RGBA) {
+	b := dst.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		t := float64(y-b.Min.Y) / float64(b.Dy())
+		c := color.RGBA{
+			R: uint8(float64(top.R)*(1-t) + float64(bottom.R)*t),
+			G: uint8(float64(top.G)*(1-t) + float64(bottom.G)*t),
+			B: uint8(float64(top.B)*(1-t) + float64(bottom.B)*t),
+			A: 255,
+		}
+		draw.Draw(dst, image.Rect(b.Min.X, y, b.Max.X, y+1), image.NewUniform(c), image.Point{}, draw.Src)
+	}
+}
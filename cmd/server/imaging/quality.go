@@ -0,0 +1,203 @@
+package imaging
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"math/bits"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	xdraw "golang.org/x/image/draw"
+)
+
+// Screenshot quality thresholds
+const (
+	// Pages whose luminance barely varies are blank (all white, loading spinners)
+	minLuminanceStdDev = 6.0
+	// Pages covered almost entirely by one color are blank or overlaid
+	maxDominantColorRatio = 0.98
+	// Maximum dHash Hamming distance to count as a match against a bad template
+	maxTemplateDistance = 10
+)
+
+// Size of the thumbnail used for color statistics
+const analysisSize = 64
+
+// Template is the perceptual hash of a known-bad screenshot, such as a
+// Cloudflare challenge or a full-page cookie wall
+type Template struct {
+	Name string
+	Hash uint64
+}
+
+// ScreenshotAnalysis describes whether a captured screenshot is worth publishing
+type ScreenshotAnalysis struct {
+	LuminanceStdDev    float64
+	DominantColor      color.RGBA
+	DominantColorRatio float64
+	Hash               uint64
+	// MatchedTemplate is the name of the bad template the screenshot resembles
+	MatchedTemplate string
+	Usable          bool
+	// Reason explains why an unusable screenshot was rejected
+	Reason string
+}
+
+// AnalyzeScreenshot checks a screenshot for blank pages and known blocking pages
+func AnalyzeScreenshot(data []byte, templates []Template) (*ScreenshotAnalysis, error) {
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode screenshot: %w", err)
+	}
+
+	thumb := image.NewRGBA(image.Rect(0, 0, analysisSize, analysisSize))
+	xdraw.ApproxBiLinear.Scale(thumb, thumb.Bounds(), src, src.Bounds(), xdraw.Src, nil)
+
+	analysis := &ScreenshotAnalysis{Hash: DHash(src), Usable: true}
+	analysis.LuminanceStdDev = luminanceStdDev(thumb)
+	analysis.DominantColor, analysis.DominantColorRatio = dominantColor(thumb)
+
+	for _, t := range templates {
+		if bits.OnesCount64(analysis.Hash^t.Hash) <= maxTemplateDistance {
+			analysis.MatchedTemplate = t.Name
+			break
+		}
+	}
+
+	switch {
+	case analysis.LuminanceStdDev < minLuminanceStdDev:
+		analysis.Usable = false
+		analysis.Reason = fmt.Sprintf("blank page (luminance std dev %.1f)", analysis.LuminanceStdDev)
+	case analysis.DominantColorRatio > maxDominantColorRatio:
+		analysis.Usable = false
+		analysis.Reason = fmt.Sprintf("single color covers %.1f%% of the page", analysis.DominantColorRatio*100)
+	case analysis.MatchedTemplate != "":
+		analysis.Usable = false
+		analysis.Reason = fmt.Sprintf("matches known bad page %q", analysis.MatchedTemplate)
+	}
+
+	return analysis, nil
+}
+
+// DHash computes a 64-bit difference hash: the image is reduced to 9x8
+// grayscale and each bit records whether a pixel is brighter than its right neighbour
+func DHash(src image.Image) uint64 {
+	small := image.NewGray(image.Rect(0, 0, 9, 8))
+	xdraw.ApproxBiLinear.Scale(small, small.Bounds(), src, src.Bounds(), xdraw.Src, nil)
+
+	var hash uint64
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			hash <<= 1
+			if small.GrayAt(x, y).Y > small.GrayAt(x+1, y).Y {
+				hash |= 1
+			}
+		}
+	}
+	return hash
+}
+
+// luminanceStdDev returns the standard deviation of pixel luminance (0-255)
+func luminanceStdDev(img *image.RGBA) float64 {
+	var sum, sumSq float64
+	n := 0
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := img.RGBAAt(x, y)
+			l := 0.299*float64(c.R) + 0.587*float64(c.G) + 0.114*float64(c.B)
+			sum += l
+			sumSq += l * l
+			n++
+		}
+	}
+	mean := sum / float64(n)
+	return math.Sqrt(math.Max(sumSq/float64(n)-mean*mean, 0))
+}
+
+// dominantColor quantizes pixels to 4 bits per channel and returns the most
+// common bucket (as its center color) and the share of pixels it covers
+func dominantColor(img *image.RGBA) (color.RGBA, float64) {
+	counts := make(map[uint16]int)
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := img.RGBAAt(x, y)
+			counts[uint16(c.R>>4)<<8|uint16(c.G>>4)<<4|uint16(c.B>>4)]++
+		}
+	}
+
+	var best uint16
+	bestCount := -1
+	for bucket, count := range counts {
+		if count > bestCount || (count == bestCount && bucket < best) {
+			best, bestCount = bucket, count
+		}
+	}
+
+	dominant := color.RGBA{
+		R: uint8(best>>8&0xF)<<4 | 0x8,
+		G: uint8(best>>4&0xF)<<4 | 0x8,
+		B: uint8(best&0xF)<<4 | 0x8,
+		A: 0xFF,
+	}
+	return dominant, float64(bestCount) / float64(b.Dx()*b.Dy())
+}
+
+// LoadTemplates hashes every PNG and JPEG in dir as a known-bad template
+// Template names are the file names without extension
+func LoadTemplates(dir string) ([]Template, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read templates directory: %w", err)
+	}
+
+	var templates []Template
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if entry.IsDir() || (ext != ".png" && ext != ".jpg" && ext != ".jpeg") {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template %s: %w", entry.Name(), err)
+		}
+		img, _, err := image.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode template %s: %w", entry.Name(), err)
+		}
+
+		templates = append(templates, Template{
+			Name: strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name())),
+			Hash: DHash(img),
+		})
+	}
+	return templates, nil
+}
+
+// ParseTemplateHashes parses "name=hexhash,name=hexhash" into templates
+func ParseTemplateHashes(raw string) ([]Template, error) {
+	var templates []Template
+	for _, pair := range strings.Split(raw, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, hexHash, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid template %q: expected name=hash", pair)
+		}
+		hash, err := strconv.ParseUint(strings.TrimPrefix(strings.TrimSpace(hexHash), "0x"), 16, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid template hash %q: %w", hexHash, err)
+		}
+		templates = append(templates, Template{Name: strings.TrimSpace(name), Hash: hash})
+	}
+	return templates, nil
+}
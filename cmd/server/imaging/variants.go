@@ -0,0 +1,150 @@
+package imaging
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	xdraw "golang.org/x/image/draw"
+)
+
+// Variant formats
+const (
+	FormatJPEG = "jpeg"
+	FormatWebP = "webp"
+)
+
+// Encoding quality for compressed variants
+const (
+	jpegQuality = 82
+	webpQuality = 80
+)
+
+// DefaultVariantWidths are the widths generated for responsive images
+var DefaultVariantWidths = []int{320, 640, 1280}
+
+// Variant is a resized and re-encoded copy of an image
+type Variant struct {
+	Width       int
+	Height      int
+	Format      string
+	ContentType string
+	Data        []byte
+}
+
+// Extension returns the file extension for the variant's format
+func (v Variant) Extension() string {
+	if v.Format == FormatWebP {
+		return ".webp"
+	}
+	return ".jpg"
+}
+
+// VariantEncoder produces responsive image variants
+// WebP encoding shells out to cwebp since there is no pure Go encoder;
+// when cwebp is unavailable only JPEG variants are produced
+type VariantEncoder struct {
+	widths    []int
+	cwebpPath string
+}
+
+// NewVariantEncoder creates an encoder for the given widths
+// An empty cwebpPath defaults to "cwebp" on PATH
+func NewVariantEncoder(widths []int, cwebpPath string) *VariantEncoder {
+	if len(widths) == 0 {
+		widths = DefaultVariantWidths
+	}
+	if cwebpPath == "" {
+		cwebpPath = "cwebp"
+	}
+	return &VariantEncoder{widths: widths, cwebpPath: cwebpPath}
+}
+
+// Generate decodes src and returns JPEG and WebP variants for each width
+// Widths larger than the source are skipped to avoid upscaling
+func (e *VariantEncoder) Generate(src []byte) ([]Variant, error) {
+	img, _, err := image.Decode(bytes.NewReader(src))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	webpAvailable := true
+	if _, err := exec.LookPath(e.cwebpPath); err != nil {
+		log.Printf("WARNING: %s not found, skipping WebP variants\n", e.cwebpPath)
+		webpAvailable = false
+	}
+
+	bounds := img.Bounds()
+	var variants []Variant
+	for _, width := range e.widths {
+		if width <= 0 || width > bounds.Dx() {
+			continue
+		}
+		height := bounds.Dy() * width / bounds.Dx()
+		resized := image.NewRGBA(image.Rect(0, 0, width, height))
+		xdraw.CatmullRom.Scale(resized, resized.Bounds(), img, bounds, xdraw.Src, nil)
+
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, resized, &jpeg.Options{Quality: jpegQuality}); err != nil {
+			return nil, fmt.Errorf("failed to encode %dw JPEG: %w", width, err)
+		}
+		variants = append(variants, Variant{
+			Width:       width,
+			Height:      height,
+			Format:      FormatJPEG,
+			ContentType: "image/jpeg",
+			Data:        buf.Bytes(),
+		})
+
+		if !webpAvailable {
+			continue
+		}
+		webp, err := e.encodeWebP(resized)
+		if err != nil {
+			log.Printf("WARNING: Failed to encode %dw WebP: %v\n", width, err)
+			continue
+		}
+		variants = append(variants, Variant{
+			Width:       width,
+			Height:      height,
+			Format:      FormatWebP,
+			ContentType: "image/webp",
+			Data:        webp,
+		})
+	}
+
+	return variants, nil
+}
+
+// encodeWebP converts img to WebP using the cwebp binary
+func (e *VariantEncoder) encodeWebP(img image.Image) ([]byte, error) {
+	dir, err := os.MkdirTemp("", "startupdose-webp-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	in := filepath.Join(dir, "in.png")
+	out := filepath.Join(dir, "out.webp")
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode PNG: %w", err)
+	}
+	if err := os.WriteFile(in, buf.Bytes(), 0o600); err != nil {
+		return nil, fmt.Errorf("failed to write temp image: %w", err)
+	}
+
+	cmd := exec.Command(e.cwebpPath, "-quiet", "-q", fmt.Sprint(webpQuality), in, "-o", out)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("cwebp failed: %w: %s", err, output)
+	}
+
+	return os.ReadFile(out)
+}
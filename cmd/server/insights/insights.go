@@ -0,0 +1,143 @@
+// Package insights collects Instagram metrics for posted companies. Each run
+// fetches the lifetime metrics of recent feed posts and appends a snapshot,
+// building a time series per post.
+package insights
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"startupdose.com/cmd/server/config"
+	"startupdose.com/cmd/server/database"
+	"startupdose.com/cmd/server/igtoken"
+	"startupdose.com/cmd/server/instagram"
+	"startupdose.com/cmd/server/models"
+)
+
+// Defaults used when Options leaves a field zero
+const (
+	DefaultInterval = 6 * time.Hour
+	DefaultMaxAge   = 30 * 24 * time.Hour
+)
+
+// Options controls collection
+type Options struct {
+	Enabled    bool
+	UserID     string
+	APIVersion string
+	// Interval is the time between runs
+	Interval time.Duration
+	// MaxAge stops collecting for posts published longer ago than this;
+	// their metrics have settled by then
+	MaxAge time.Duration
+}
+
+// Report summarizes a collection run
+type Report struct {
+	Posts   int `json:"posts"`
+	Fetched int `json:"fetched"`
+	Failed  int `json:"failed"`
+}
+
+// NewOptions reads Options from cfg, using the defaults for invalid durations
+func NewOptions(cfg *config.Config) Options {
+	opts := Options{
+		Enabled:    cfg.IGInsightsEnabled,
+		UserID:     cfg.IGUserID,
+		APIVersion: cfg.IGAPIVersion,
+	}
+
+	if d, err := time.ParseDuration(cfg.IGInsightsInterval); err == nil && d > 0 {
+		opts.Interval = d
+	} else {
+		log.Printf("WARNING: Invalid IG_INSIGHTS_INTERVAL %q, using %s\n", cfg.IGInsightsInterval, DefaultInterval)
+	}
+	if d, err := time.ParseDuration(cfg.IGInsightsMaxAge); err == nil && d > 0 {
+		opts.MaxAge = d
+	} else {
+		log.Printf("WARNING: Invalid IG_INSIGHTS_MAX_AGE %q, using %s\n", cfg.IGInsightsMaxAge, DefaultMaxAge)
+	}
+	return opts
+}
+
+// Run collects immediately and then every Interval until ctx is done
+func Run(ctx context.Context, opts Options) {
+	if !opts.Enabled || opts.UserID == "" {
+		log.Println("INFO: Instagram insights collection disabled")
+		return
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultInterval
+	}
+
+	for {
+		report, err := Collect(ctx, opts)
+		if err != nil {
+			log.Printf("ERROR: Instagram insights collection failed: %v\n", err)
+		} else {
+			log.Printf("INFO: Collected Instagram insights for %d/%d posts (%d failed)\n", report.Fetched, report.Posts, report.Failed)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(opts.Interval):
+		}
+	}
+}
+
+// Collect fetches and stores the metrics of every feed post published within MaxAge
+// A post that fails is logged and counted; the others are still collected
+func Collect(ctx context.Context, opts Options) (*Report, error) {
+	if opts.MaxAge <= 0 {
+		opts.MaxAge = DefaultMaxAge
+	}
+
+	accessToken := igtoken.AccessToken()
+	if opts.UserID == "" || accessToken == "" {
+		return nil, fmt.Errorf("Instagram credentials are not configured")
+	}
+
+	since := time.Now().Add(-opts.MaxAge)
+	posts, err := database.NewSocialPostRepository().ListPostedSince(models.SocialChannelInstagram, since)
+	if err != nil {
+		return nil, err
+	}
+
+	client := instagram.NewClient(opts.UserID, accessToken, opts.APIVersion)
+	repo := database.NewSocialPostInsightRepository()
+	report := &Report{Posts: len(posts)}
+	for _, post := range posts {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		metrics, err := client.MediaInsights(ctx, *post.MediaID)
+		if err != nil {
+			log.Printf("WARNING: Failed to fetch insights for media %s (company %s): %v\n", *post.MediaID, post.CompanyID, err)
+			report.Failed++
+			continue
+		}
+
+		if err := repo.Insert(&models.SocialPostInsight{
+			SocialPostID: post.ID,
+			CompanyID:    post.CompanyID,
+			MediaID:      *post.MediaID,
+			Reach:        metrics.Reach,
+			Impressions:  metrics.Impressions,
+			Likes:        metrics.Likes,
+			Comments:     metrics.Comments,
+			Saves:        metrics.Saves,
+			FetchedAt:    time.Now(),
+		}); err != nil {
+			log.Printf("WARNING: Failed to store insights for media %s: %v\n", *post.MediaID, err)
+			report.Failed++
+			continue
+		}
+		report.Fetched++
+	}
+
+	return report, nil
+}
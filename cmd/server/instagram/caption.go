@@ -0,0 +1,332 @@
+package instagram
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/rivo/uniseg"
+)
+
+// Caption limits enforced by Instagram
+const (
+	// maxCaptionLength is counted in characters, not bytes
+	maxCaptionLength = 2200
+	MaxHashtags      = 30
+)
+
+// Caption channels, each rendered with its own template
+const (
+	CaptionChannelFeed = "feed"
+	CaptionChannelReel = "reel"
+)
+
+// captionEllipsis marks text cut to fit the caption limit
+const captionEllipsis = "…"
+
+// DefaultCaptionTemplates are used for channels without a configured template
+var DefaultCaptionTemplates = map[string]string{
+	CaptionChannelFeed: `Today's Fix 💊⚡
+
+{{.Name}}{{with .Mention}} ({{.}}){{end}}
+
+{{.Description}}
+{{- if .Bullets}}
+
+Why we like it:
+{{- range .Bullets}}
+• {{.}}
+{{- end}}
+{{- end}}
+
+Learn more: {{.Website}}
+{{- with .Hashtags}}
+
+{{join . " "}}
+{{- end}}`,
+
+	CaptionChannelReel: `{{.Name}}{{with .Mention}} ({{.}}){{end}}: {{.Description}}
+
+Learn more: {{.Website}}
+{{- with .Hashtags}}
+
+{{join . " "}}
+{{- end}}`,
+}
+
+// DefaultHashtags start every caption's hashtags unless others are configured
+var DefaultHashtags = []string{"#startupdose", "#startups", "#tech", "#innovation"}
+
+// CaptionData is the company data a caption is rendered from
+type CaptionData struct {
+	Name        string
+	Description string
+	// Bullets are the plain-text appeal items; they are dropped from the end
+	// first when the caption is too long
+	Bullets []string
+	Website string
+	// InstagramURL is the company's Instagram profile, mentioned when set
+	InstagramURL string
+	// Category and Keywords add hashtags relevant to the company, see GenerateHashtags
+	Category string
+	Keywords []string
+}
+
+// CaptionOptions configures a CaptionBuilder
+type CaptionOptions struct {
+	// Templates maps a channel to its template text; missing channels use
+	// DefaultCaptionTemplates
+	Templates map[string]string
+	// Hashtags come before the generated ones, with or without the leading #;
+	// nil uses DefaultHashtags
+	Hashtags []string
+	// MaxHashtags caps the number of hashtags, at most (and by default) MaxHashtags
+	MaxHashtags int
+	// FirstComment leaves hashtags out of the caption; post FirstComment's
+	// text as the first comment instead
+	FirstComment bool
+}
+
+// captionView is what caption templates render: .Name, .Description,
+// .Bullets, .Website, .Mention ("@handle" or empty) and .Hashtags, plus the
+// join function
+type captionView struct {
+	Name        string
+	Description string
+	Bullets     []string
+	Website     string
+	Mention     string
+	Hashtags    []string
+}
+
+// CaptionBuilder renders captions from per-channel templates
+type CaptionBuilder struct {
+	templates    map[string]*template.Template
+	hashtags     []string
+	maxHashtags  int
+	firstComment bool
+}
+
+// NewCaptionBuilder parses the caption templates
+func NewCaptionBuilder(opts CaptionOptions) (*CaptionBuilder, error) {
+	funcs := template.FuncMap{"join": strings.Join}
+
+	texts := make(map[string]string, len(DefaultCaptionTemplates))
+	for channel, text := range DefaultCaptionTemplates {
+		texts[channel] = text
+	}
+	for channel, text := range opts.Templates {
+		texts[channel] = text
+	}
+
+	templates := make(map[string]*template.Template, len(texts))
+	for channel, text := range texts {
+		tmpl, err := template.New(channel).Funcs(funcs).Option("missingkey=error").Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s caption template: %w", channel, err)
+		}
+		templates[channel] = tmpl
+	}
+
+	hashtags := opts.Hashtags
+	if hashtags == nil {
+		hashtags = DefaultHashtags
+	}
+	maxHashtags := opts.MaxHashtags
+	if maxHashtags <= 0 || maxHashtags > MaxHashtags {
+		maxHashtags = MaxHashtags
+	}
+
+	return &CaptionBuilder{
+		templates:    templates,
+		hashtags:     hashtags,
+		maxHashtags:  maxHashtags,
+		firstComment: opts.FirstComment,
+	}, nil
+}
+
+// Build renders the caption for a channel, falling back to the feed template
+// for unknown channels. Captions over Instagram's limit lose appeal bullets
+// from the end first, then description text, and are cut as a last resort;
+// text is only ever cut between grapheme clusters so emoji stay intact.
+func (b *CaptionBuilder) Build(channel string, data CaptionData) (string, error) {
+	tmpl, ok := b.templates[channel]
+	if !ok {
+		tmpl = b.templates[CaptionChannelFeed]
+	}
+
+	view := captionView{
+		Name:        data.Name,
+		Description: data.Description,
+		Bullets:     data.Bullets,
+		Website:     data.Website,
+		Mention:     mention(InstagramHandle(data.InstagramURL)),
+	}
+	if !b.firstComment {
+		view.Hashtags = b.Hashtags(data)
+	}
+
+	caption, err := renderCaption(tmpl, view)
+	if err != nil {
+		return "", err
+	}
+	for utf8.RuneCountInString(caption) > maxCaptionLength && len(view.Bullets) > 0 {
+		view.Bullets = view.Bullets[:len(view.Bullets)-1]
+		if caption, err = renderCaption(tmpl, view); err != nil {
+			return "", err
+		}
+	}
+
+	if over := utf8.RuneCountInString(caption) - maxCaptionLength; over > 0 && view.Description != "" {
+		keep := utf8.RuneCountInString(view.Description) - over
+		if keep > 0 {
+			view.Description = truncateText(view.Description, keep)
+			if caption, err = renderCaption(tmpl, view); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	return truncateCaption(caption), nil
+}
+
+// Hashtags returns the configured hashtags followed by those generated for the
+// company, without duplicates and capped at MaxHashtags
+func (b *CaptionBuilder) Hashtags(data CaptionData) []string {
+	tags := append(append([]string{}, b.hashtags...), GenerateHashtags(data.Category, data.Keywords, data.Description)...)
+	return normalizeHashtags(tags, b.maxHashtags)
+}
+
+// FirstComment returns the text to post as the first comment, or "" when
+// hashtags are part of the caption
+func (b *CaptionBuilder) FirstComment(data CaptionData) string {
+	if !b.firstComment {
+		return ""
+	}
+	return strings.Join(b.Hashtags(data), " ")
+}
+
+// renderCaption executes a caption template
+func renderCaption(tmpl *template.Template, view captionView) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, view); err != nil {
+		return "", fmt.Errorf("failed to render %s caption: %w", tmpl.Name(), err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// truncateCaption ensures the caption doesn't exceed Instagram's limit
+func truncateCaption(caption string) string {
+	return truncateText(caption, maxCaptionLength)
+}
+
+// truncateText shortens s to at most limit characters including a trailing
+// ellipsis, cutting only between grapheme clusters
+func truncateText(s string, limit int) string {
+	if utf8.RuneCountInString(s) <= limit {
+		return s
+	}
+
+	budget := limit - utf8.RuneCountInString(captionEllipsis)
+	var out strings.Builder
+	count := 0
+	graphemes := uniseg.NewGraphemes(s)
+	for graphemes.Next() {
+		cluster := graphemes.Str()
+		n := utf8.RuneCountInString(cluster)
+		if count+n > budget {
+			break
+		}
+		out.WriteString(cluster)
+		count += n
+	}
+	return strings.TrimRightFunc(out.String(), unicode.IsSpace) + captionEllipsis
+}
+
+// handlePattern matches a valid Instagram username
+var handlePattern = regexp.MustCompile(`^[A-Za-z0-9._]{1,30}$`)
+
+// nonProfilePaths are instagram.com paths that aren't usernames
+var nonProfilePaths = map[string]bool{
+	"p":        true,
+	"reel":     true,
+	"reels":    true,
+	"tv":       true,
+	"stories":  true,
+	"explore":  true,
+	"accounts": true,
+	"direct":   true,
+}
+
+// InstagramHandle extracts the username from an Instagram profile URL such as
+// https://www.instagram.com/acme/ or instagram.com/acme, or from "@acme"
+// Returns "" when the value isn't a profile
+func InstagramHandle(profile string) string {
+	profile = strings.TrimSpace(profile)
+	if profile == "" {
+		return ""
+	}
+
+	var handle string
+	if strings.HasPrefix(profile, "@") {
+		handle = strings.TrimPrefix(profile, "@")
+	} else {
+		if !strings.Contains(profile, "://") {
+			profile = "https://" + profile
+		}
+		u, err := url.Parse(profile)
+		if err != nil {
+			return ""
+		}
+		host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+		if host != "instagram.com" && host != "m.instagram.com" {
+			return ""
+		}
+		handle, _, _ = strings.Cut(strings.Trim(u.Path, "/"), "/")
+	}
+
+	if !handlePattern.MatchString(handle) || nonProfilePaths[strings.ToLower(handle)] {
+		return ""
+	}
+	return handle
+}
+
+// mention formats a handle as "@handle", or "" for no handle
+func mention(handle string) string {
+	if handle == "" {
+		return ""
+	}
+	return "@" + handle
+}
+
+// normalizeHashtags prefixes each tag with #, drops tags that are empty or
+// contain characters Instagram doesn't allow, removes case-insensitive
+// duplicates and keeps at most limit tags
+func normalizeHashtags(tags []string, limit int) []string {
+	seen := make(map[string]bool, len(tags))
+	var out []string
+	for _, tag := range tags {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "#")
+		if tag == "" || strings.IndexFunc(tag, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+		}) >= 0 {
+			continue
+		}
+
+		key := strings.ToLower(tag)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, "#"+tag)
+		if len(out) == limit {
+			break
+		}
+	}
+	return out
+}
@@ -0,0 +1,92 @@
+package instagram
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+)
+
+// Instagram carousels hold between 2 and 10 items
+const (
+	minCarouselItems = 2
+	maxCarouselItems = 10
+)
+
+// PublishCarousel publishes several images as a single carousel post
+// Every image gets its own child container; the caption goes on the carousel
+func (c *Client) PublishCarousel(ctx context.Context, imageURLs []string, caption string) (*PublishResult, error) {
+	if !c.IsConfigured() {
+		return &PublishResult{Posted: false, Error: "Instagram client not configured"}, nil
+	}
+	if len(imageURLs) < minCarouselItems || len(imageURLs) > maxCarouselItems {
+		return &PublishResult{Posted: false, Error: fmt.Sprintf("carousel needs %d to %d images, got %d", minCarouselItems, maxCarouselItems, len(imageURLs))}, nil
+	}
+	if refused := c.checkQuota(ctx); refused != nil {
+		return refused, nil
+	}
+
+	// Step 1: Create a child container per image
+	childIDs := make([]string, 0, len(imageURLs))
+	for i, imageURL := range imageURLs {
+		childID, err := c.createCarouselItemContainer(ctx, imageURL)
+		if err != nil {
+			return &PublishResult{Posted: false, Error: fmt.Sprintf("failed to create carousel item %d: %v", i+1, err)}, nil
+		}
+		log.Printf("Instagram: Created carousel item container %d/%d: %s", i+1, len(imageURLs), childID)
+		childIDs = append(childIDs, childID)
+	}
+
+	// Step 2: Wait for every child to finish processing
+	for i, childID := range childIDs {
+		if err := c.waitForContainerReady(ctx, childID); err != nil {
+			return &PublishResult{Posted: false, Error: fmt.Sprintf("carousel item %d not ready: %v", i+1, err)}, nil
+		}
+	}
+	log.Printf("Instagram: All %d carousel items ready", len(childIDs))
+
+	// Step 3: Create the carousel container referencing the children
+	containerID, err := c.createCarouselContainer(ctx, childIDs, caption)
+	if err != nil {
+		return &PublishResult{Posted: false, Error: fmt.Sprintf("failed to create carousel container: %v", err)}, nil
+	}
+	log.Printf("Instagram: Created carousel container: %s", containerID)
+
+	// Step 4: Poll the carousel container until FINISHED
+	if err := c.waitForContainerReady(ctx, containerID); err != nil {
+		return &PublishResult{Posted: false, ContainerID: containerID, Error: fmt.Sprintf("container not ready: %v", err)}, nil
+	}
+	log.Printf("Instagram: Carousel container ready for publishing")
+
+	// Step 5: Publish the carousel
+	mediaID, err := c.publishContainer(ctx, containerID)
+	if err != nil {
+		return &PublishResult{Posted: false, ContainerID: containerID, Error: fmt.Sprintf("failed to publish: %v", err)}, nil
+	}
+	log.Printf("Instagram: Successfully published carousel with media ID: %s", mediaID)
+
+	return &PublishResult{
+		MediaID:     mediaID,
+		Posted:      true,
+		ContainerID: containerID,
+		Permalink:   c.permalink(ctx, mediaID),
+	}, nil
+}
+
+// createCarouselItemContainer creates a child container for one carousel image
+func (c *Client) createCarouselItemContainer(ctx context.Context, imageURL string) (string, error) {
+	data := url.Values{}
+	data.Set("image_url", imageURL)
+	data.Set("is_carousel_item", "true")
+	return c.createContainer(ctx, data)
+}
+
+// createCarouselContainer creates the parent CAROUSEL container
+func (c *Client) createCarouselContainer(ctx context.Context, childIDs []string, caption string) (string, error) {
+	data := url.Values{}
+	data.Set("media_type", "CAROUSEL")
+	data.Set("children", strings.Join(childIDs, ","))
+	data.Set("caption", truncateCaption(caption))
+	return c.createContainer(ctx, data)
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"log"
 	"net/http"
 	"net/url"
@@ -13,19 +12,41 @@ import (
 )
 
 const (
-	graphAPIBaseURL    = "https://graph.facebook.com"
-	defaultAPIVersion  = "v23.0"
-	maxCaptionLength   = 2200
-	containerPollDelay = 2 * time.Second
-	maxPollAttempts    = 15
+	graphAPIBaseURL   = "https://graph.facebook.com"
+	defaultAPIVersion = "v23.0"
+
+	// Each poll waits this much longer than the previous one, up to MaxDelay
+	pollBackoffFactor = 1.5
 )
 
+// PollConfig controls how container status is polled before publishing
+type PollConfig struct {
+	// InitialDelay is the wait before the second status check
+	InitialDelay time.Duration
+	// MaxDelay caps the wait between checks as it backs off
+	MaxDelay time.Duration
+	// MaxAttempts is the number of status checks for image containers
+	MaxAttempts int
+	// VideoMaxAttempts replaces MaxAttempts for video containers, which are
+	// transcoded before they can be published
+	VideoMaxAttempts int
+}
+
+// DefaultPollConfig is used for fields left zero
+var DefaultPollConfig = PollConfig{
+	InitialDelay:     2 * time.Second,
+	MaxDelay:         10 * time.Second,
+	MaxAttempts:      15,
+	VideoMaxAttempts: 40,
+}
+
 // Client represents an Instagram Graph API client
 type Client struct {
 	userID      string
 	accessToken string
 	apiVersion  string
 	httpClient  *http.Client
+	poll        PollConfig
 }
 
 // PublishResult contains the result of a successful Instagram post
@@ -33,6 +54,13 @@ type PublishResult struct {
 	MediaID   string `json:"media_id"`
 	Posted    bool   `json:"posted"`
 	Error     string `json:"error,omitempty"`
+	// ContainerID is set once the media container was created, even if publishing failed
+	ContainerID string `json:"container_id,omitempty"`
+	// Permalink is the public URL of the published post, when Instagram returned it
+	Permalink string `json:"permalink,omitempty"`
+	// QuotaExhausted is true when posting was refused because the account
+	// reached its publishing limit; retry once the window has moved on
+	QuotaExhausted bool `json:"quota_exhausted,omitempty"`
 }
 
 // containerResponse represents the response from creating a media container
@@ -47,7 +75,9 @@ type containerResponse struct {
 // containerStatusResponse represents the response from checking container status
 type containerStatusResponse struct {
 	StatusCode string `json:"status_code"`
-	Error      *struct {
+	// Status carries the detail behind an ERROR status code
+	Status string `json:"status"`
+	Error  *struct {
 		Message string `json:"message"`
 		Code    int    `json:"code"`
 	} `json:"error,omitempty"`
@@ -74,7 +104,29 @@ func NewClient(userID, accessToken, apiVersion string) *Client {
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		poll: DefaultPollConfig,
+	}
+}
+
+// WithPollConfig sets how container status is polled; zero fields keep their defaults
+func (c *Client) WithPollConfig(cfg PollConfig) *Client {
+	if cfg.InitialDelay <= 0 {
+		cfg.InitialDelay = DefaultPollConfig.InitialDelay
+	}
+	if cfg.MaxDelay <= 0 {
+		cfg.MaxDelay = DefaultPollConfig.MaxDelay
+	}
+	if cfg.MaxDelay < cfg.InitialDelay {
+		cfg.MaxDelay = cfg.InitialDelay
 	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = DefaultPollConfig.MaxAttempts
+	}
+	if cfg.VideoMaxAttempts <= 0 {
+		cfg.VideoMaxAttempts = DefaultPollConfig.VideoMaxAttempts
+	}
+	c.poll = cfg
+	return c
 }
 
 // IsConfigured returns true if the client has required credentials
@@ -87,6 +139,9 @@ func (c *Client) PublishPost(ctx context.Context, imageURL, caption string) (*Pu
 	if !c.IsConfigured() {
 		return &PublishResult{Posted: false, Error: "Instagram client not configured"}, nil
 	}
+	if refused := c.checkQuota(ctx); refused != nil {
+		return refused, nil
+	}
 
 	// Step 1: Create media container
 	containerID, err := c.createMediaContainer(ctx, imageURL, caption)
@@ -97,32 +152,36 @@ func (c *Client) PublishPost(ctx context.Context, imageURL, caption string) (*Pu
 
 	// Step 2: Poll for container status until FINISHED
 	if err := c.waitForContainerReady(ctx, containerID); err != nil {
-		return &PublishResult{Posted: false, Error: fmt.Sprintf("container not ready: %v", err)}, nil
+		return &PublishResult{Posted: false, ContainerID: containerID, Error: fmt.Sprintf("container not ready: %v", err)}, nil
 	}
 	log.Printf("Instagram: Container ready for publishing")
 
 	// Step 3: Publish the container
 	mediaID, err := c.publishContainer(ctx, containerID)
 	if err != nil {
-		return &PublishResult{Posted: false, Error: fmt.Sprintf("failed to publish: %v", err)}, nil
+		return &PublishResult{Posted: false, ContainerID: containerID, Error: fmt.Sprintf("failed to publish: %v", err)}, nil
 	}
 	log.Printf("Instagram: Successfully published post with media ID: %s", mediaID)
 
 	return &PublishResult{
-		MediaID: mediaID,
-		Posted:  true,
+		MediaID:     mediaID,
+		Posted:      true,
+		ContainerID: containerID,
+		Permalink:   c.permalink(ctx, mediaID),
 	}, nil
 }
 
 // createMediaContainer creates a media container for the image
 func (c *Client) createMediaContainer(ctx context.Context, imageURL, caption string) (string, error) {
-	endpoint := fmt.Sprintf("%s/%s/%s/media", graphAPIBaseURL, c.apiVersion, c.userID)
-
-	// Build form data
 	data := url.Values{}
 	data.Set("image_url", imageURL)
 	data.Set("caption", truncateCaption(caption))
-	data.Set("access_token", c.accessToken)
+	return c.createContainer(ctx, data)
+}
+
+// createContainer creates a media container with the given parameters
+func (c *Client) createContainer(ctx context.Context, data url.Values) (string, error) {
+	endpoint := fmt.Sprintf("%s/%s/%s/media", graphAPIBaseURL, c.apiVersion, c.userID)
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(data.Encode()))
 	if err != nil {
@@ -130,15 +189,9 @@ func (c *Client) createMediaContainer(ctx context.Context, imageURL, caption str
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
-	resp, err := c.httpClient.Do(req)
+	body, err := c.do(req, c.accessToken)
 	if err != nil {
-		return "", fmt.Errorf("failed to send request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", fmt.Errorf("failed to read response: %w", err)
+		return "", err
 	}
 
 	var result containerResponse
@@ -157,37 +210,50 @@ func (c *Client) createMediaContainer(ctx context.Context, imageURL, caption str
 	return result.ID, nil
 }
 
-// waitForContainerReady polls the container status until it's FINISHED
+// waitForContainerReady polls an image container until it's FINISHED
 func (c *Client) waitForContainerReady(ctx context.Context, containerID string) error {
-	endpoint := fmt.Sprintf("%s/%s/%s?fields=status_code&access_token=%s",
-		graphAPIBaseURL, c.apiVersion, containerID, c.accessToken)
+	return c.pollContainer(ctx, containerID, c.poll.MaxAttempts)
+}
 
-	for attempt := 0; attempt < maxPollAttempts; attempt++ {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-		}
+// waitForVideoContainerReady polls a video container, allowing time for transcoding
+func (c *Client) waitForVideoContainerReady(ctx context.Context, containerID string) error {
+	return c.pollContainer(ctx, containerID, c.poll.VideoMaxAttempts)
+}
 
-		req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
-		if err != nil {
-			return fmt.Errorf("failed to create request: %w", err)
-		}
+// ContainerError reports a container that Instagram could not publish
+type ContainerError struct {
+	ContainerID string
+	// StatusCode is ERROR or EXPIRED
+	StatusCode string
+	// Status is Instagram's detail, e.g. "Error: Media download has failed."
+	Status string
+}
 
-		resp, err := c.httpClient.Do(req)
-		if err != nil {
-			return fmt.Errorf("failed to send request: %w", err)
-		}
+// Error implements the error interface
+func (e *ContainerError) Error() string {
+	msg := "container processing failed"
+	if e.StatusCode == "EXPIRED" {
+		msg = "container expired"
+	}
+	if e.Status != "" {
+		msg += ": " + e.Status
+	}
+	return msg
+}
 
-		body, err := io.ReadAll(resp.Body)
-		resp.Body.Close()
-		if err != nil {
-			return fmt.Errorf("failed to read response: %w", err)
-		}
+// pollContainer checks the container status until it's FINISHED, waiting
+// longer between checks each time and returning early when ctx is done
+func (c *Client) pollContainer(ctx context.Context, containerID string, maxAttempts int) error {
+	params := url.Values{}
+	params.Set("fields", "status_code,status")
+	endpoint := fmt.Sprintf("%s/%s/%s?%s", graphAPIBaseURL, c.apiVersion, containerID, params.Encode())
 
+	delay := c.poll.InitialDelay
+	var lastStatus string
+	for attempt := 1; ; attempt++ {
 		var result containerStatusResponse
-		if err := json.Unmarshal(body, &result); err != nil {
-			return fmt.Errorf("failed to parse response: %w", err)
+		if err := c.getJSON(ctx, endpoint, c.accessToken, &result); err != nil {
+			return err
 		}
 
 		if result.Error != nil {
@@ -195,24 +261,36 @@ func (c *Client) waitForContainerReady(ctx context.Context, containerID string)
 		}
 
 		switch result.StatusCode {
-		case "FINISHED":
+		case "FINISHED", "PUBLISHED":
 			return nil
-		case "ERROR":
-			return fmt.Errorf("container processing failed")
-		case "EXPIRED":
-			return fmt.Errorf("container expired")
-		case "IN_PROGRESS":
-			// Continue polling
-			log.Printf("Instagram: Container status: IN_PROGRESS (attempt %d/%d)", attempt+1, maxPollAttempts)
-		default:
-			// Unknown status, continue polling
-			log.Printf("Instagram: Container status: %s (attempt %d/%d)", result.StatusCode, attempt+1, maxPollAttempts)
+		case "ERROR", "EXPIRED":
+			return &ContainerError{ContainerID: containerID, StatusCode: result.StatusCode, Status: result.Status}
 		}
 
-		time.Sleep(containerPollDelay)
-	}
+		// IN_PROGRESS or an unknown status, keep polling
+		lastStatus = result.StatusCode
+		if result.Status != "" {
+			lastStatus += " (" + result.Status + ")"
+		}
+		log.Printf("Instagram: Container status: %s (attempt %d/%d)", lastStatus, attempt, maxAttempts)
+
+		if attempt >= maxAttempts {
+			return fmt.Errorf("container not ready after %d attempts, last status %s", maxAttempts, lastStatus)
+		}
 
-	return fmt.Errorf("container not ready after %d attempts", maxPollAttempts)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		delay = time.Duration(float64(delay) * pollBackoffFactor)
+		if delay > c.poll.MaxDelay {
+			delay = c.poll.MaxDelay
+		}
+	}
 }
 
 // publishContainer publishes the media container to the feed
@@ -221,7 +299,6 @@ func (c *Client) publishContainer(ctx context.Context, containerID string) (stri
 
 	data := url.Values{}
 	data.Set("creation_id", containerID)
-	data.Set("access_token", c.accessToken)
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(data.Encode()))
 	if err != nil {
@@ -229,15 +306,9 @@ func (c *Client) publishContainer(ctx context.Context, containerID string) (stri
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
-	resp, err := c.httpClient.Do(req)
+	body, err := c.do(req, c.accessToken)
 	if err != nil {
-		return "", fmt.Errorf("failed to send request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", fmt.Errorf("failed to read response: %w", err)
+		return "", err
 	}
 
 	var result publishResponse
@@ -256,36 +327,66 @@ func (c *Client) publishContainer(ctx context.Context, containerID string) (stri
 	return result.ID, nil
 }
 
-// truncateCaption ensures the caption doesn't exceed Instagram's limit
-func truncateCaption(caption string) string {
-	if len(caption) <= maxCaptionLength {
-		return caption
+// Comment posts a comment on published media and returns the comment's ID
+// Requires the instagram_manage_comments permission
+func (c *Client) Comment(ctx context.Context, mediaID, message string) (string, error) {
+	if !c.IsConfigured() {
+		return "", fmt.Errorf("Instagram client not configured")
 	}
-	// Truncate and add ellipsis
-	return caption[:maxCaptionLength-3] + "..."
-}
 
-// BuildCaption creates a caption from company data in the required format
-func BuildCaption(name, description, appeal, website string) string {
-	// Convert HTML appeal to plain text bullet points
-	plainAppeal := convertAppealToPlainText(appeal)
+	endpoint := fmt.Sprintf("%s/%s/%s/comments", graphAPIBaseURL, c.apiVersion, mediaID)
+
+	data := url.Values{}
+	data.Set("message", message)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(data.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	body, err := c.do(req, c.accessToken)
+	if err != nil {
+		return "", err
+	}
 
-	// Build the caption
-	caption := fmt.Sprintf("Today's Fix \xF0\x9F\x92\x8A\xE2\x9A\xA1\n\n%s\n\n%s\n\nWhy we like it:\n%s\n\nLearn more: %s\n\n#startupdose #startups #tech #innovation",
-		name,
-		description,
-		plainAppeal,
-		website,
-	)
+	var result publishResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
 
-	return truncateCaption(caption)
+	if result.Error != nil {
+		return "", fmt.Errorf("API error: %s (code: %d)", result.Error.Message, result.Error.Code)
+	}
+
+	if result.ID == "" {
+		return "", fmt.Errorf("no comment ID returned")
+	}
+
+	return result.ID, nil
 }
 
-// convertAppealToPlainText converts HTML <li> tags to plain text bullet points
-func convertAppealToPlainText(appeal string) string {
-	// Remove <li> and </li> tags, replace with bullet points
-	appeal = strings.ReplaceAll(appeal, "<li>", "\xE2\x80\xA2 ")
-	appeal = strings.ReplaceAll(appeal, "</li>", "\n")
-	appeal = strings.TrimSpace(appeal)
-	return appeal
+// permalink returns the public URL of a published media object
+// Failures are logged and return "", the post itself already succeeded
+func (c *Client) permalink(ctx context.Context, mediaID string) string {
+	params := url.Values{}
+	params.Set("fields", "permalink")
+	endpoint := fmt.Sprintf("%s/%s/%s?%s", graphAPIBaseURL, c.apiVersion, mediaID, params.Encode())
+
+	var result struct {
+		Permalink string `json:"permalink"`
+		Error     *struct {
+			Message string `json:"message"`
+			Code    int    `json:"code"`
+		} `json:"error,omitempty"`
+	}
+	if err := c.getJSON(ctx, endpoint, c.accessToken, &result); err != nil {
+		log.Printf("Instagram: Failed to fetch permalink for %s: %v", mediaID, err)
+		return ""
+	}
+	if result.Error != nil {
+		log.Printf("Instagram: Failed to fetch permalink for %s: %s (code: %d)", mediaID, result.Error.Message, result.Error.Code)
+		return ""
+	}
+	return result.Permalink
 }
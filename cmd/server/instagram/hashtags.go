@@ -0,0 +1,153 @@
+package instagram
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// maxGeneratedTagLength skips keywords too long to make a readable hashtag
+const maxGeneratedTagLength = 24
+
+// hashtagTaxonomy maps category and keyword terms to established hashtags, so
+// "machine learning" becomes the tags people actually follow instead of
+// #machinelearning alone
+var hashtagTaxonomy = map[string][]string{
+	"ai":                      {"#ai", "#artificialintelligence"},
+	"artificial intelligence": {"#ai", "#artificialintelligence"},
+	"machine learning":        {"#machinelearning", "#ai"},
+	"generative ai":           {"#generativeai", "#ai"},
+	"llm":                     {"#llm", "#generativeai"},
+	"fintech":                 {"#fintech", "#finance"},
+	"payments":                {"#payments", "#fintech"},
+	"banking":                 {"#banking", "#fintech"},
+	"insurance":               {"#insurtech", "#insurance"},
+	"saas":                    {"#saas", "#software"},
+	"b2b":                     {"#b2b", "#saas"},
+	"dev tools":               {"#devtools", "#developers", "#programming"},
+	"developer tools":         {"#devtools", "#developers", "#programming"},
+	"developers":              {"#developers", "#programming"},
+	"open source":             {"#opensource"},
+	"no-code":                 {"#nocode"},
+	"no code":                 {"#nocode"},
+	"cloud":                   {"#cloud", "#cloudcomputing"},
+	"cybersecurity":           {"#cybersecurity", "#infosec"},
+	"security":                {"#cybersecurity", "#infosec"},
+	"privacy":                 {"#privacy", "#dataprivacy"},
+	"data":                    {"#data", "#bigdata"},
+	"analytics":               {"#analytics", "#data"},
+	"climate":                 {"#climatetech", "#sustainability"},
+	"climate tech":            {"#climatetech", "#sustainability"},
+	"sustainability":          {"#sustainability", "#climatetech"},
+	"energy":                  {"#energy", "#cleanenergy"},
+	"clean energy":            {"#cleanenergy", "#renewableenergy"},
+	"health":                  {"#healthtech", "#digitalhealth"},
+	"health tech":             {"#healthtech", "#digitalhealth"},
+	"healthcare":              {"#healthtech", "#healthcare"},
+	"biotech":                 {"#biotech", "#lifesciences"},
+	"mental health":           {"#mentalhealth", "#digitalhealth"},
+	"fitness":                 {"#fitness", "#fittech"},
+	"edtech":                  {"#edtech", "#education"},
+	"education":               {"#edtech", "#education"},
+	"ecommerce":               {"#ecommerce", "#retail"},
+	"e-commerce":              {"#ecommerce", "#retail"},
+	"retail":                  {"#retail", "#retailtech"},
+	"marketing":               {"#marketing", "#martech"},
+	"sales":                   {"#sales", "#salestech"},
+	"hr":                      {"#hrtech", "#futureofwork"},
+	"recruiting":              {"#recruiting", "#hrtech"},
+	"remote work":             {"#remotework", "#futureofwork"},
+	"productivity":            {"#productivity", "#futureofwork"},
+	"collaboration":           {"#collaboration", "#productivity"},
+	"legal":                   {"#legaltech"},
+	"real estate":             {"#proptech", "#realestate"},
+	"proptech":                {"#proptech", "#realestate"},
+	"construction":            {"#contech", "#construction"},
+	"mobility":                {"#mobility", "#transportation"},
+	"logistics":               {"#logistics", "#supplychain"},
+	"supply chain":            {"#supplychain", "#logistics"},
+	"robotics":                {"#robotics", "#automation"},
+	"automation":              {"#automation"},
+	"hardware":                {"#hardware", "#engineering"},
+	"iot":                     {"#iot", "#hardware"},
+	"space":                   {"#spacetech", "#space"},
+	"drones":                  {"#drones", "#robotics"},
+	"blockchain":              {"#blockchain", "#web3"},
+	"crypto":                  {"#crypto", "#web3"},
+	"web3":                    {"#web3", "#blockchain"},
+	"gaming":                  {"#gaming", "#gamedev"},
+	"music":                   {"#musictech", "#music"},
+	"media":                   {"#media", "#creatoreconomy"},
+	"creators":                {"#creatoreconomy", "#contentcreator"},
+	"food":                    {"#foodtech", "#food"},
+	"agriculture":             {"#agtech", "#agriculture"},
+	"travel":                  {"#traveltech", "#travel"},
+	"design":                  {"#design", "#ux"},
+	"mobile":                  {"#mobileapp", "#apps"},
+}
+
+// taxonomyTerm is a taxonomy key matched as a whole word in descriptions
+type taxonomyTerm struct {
+	term    string
+	pattern *regexp.Regexp
+}
+
+// taxonomyTerms lists the taxonomy keys longest first, so "machine learning"
+// is found before a shorter term it contains
+var taxonomyTerms = func() []taxonomyTerm {
+	terms := make([]taxonomyTerm, 0, len(hashtagTaxonomy))
+	for term := range hashtagTaxonomy {
+		terms = append(terms, taxonomyTerm{
+			term:    term,
+			pattern: regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(term) + `\b`),
+		})
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		if len(terms[i].term) != len(terms[j].term) {
+			return len(terms[i].term) > len(terms[j].term)
+		}
+		return terms[i].term < terms[j].term
+	})
+	return terms
+}()
+
+// GenerateHashtags derives hashtags for a company from its category, its
+// keywords and taxonomy terms found in its description, most specific first
+// Terms in the taxonomy map to its curated tags; other categories and
+// keywords become a tag of their own. The result may contain duplicates.
+func GenerateHashtags(category string, keywords []string, description string) []string {
+	var tags []string
+	for _, term := range append([]string{category}, keywords...) {
+		tags = append(tags, termHashtags(term)...)
+	}
+
+	for _, t := range taxonomyTerms {
+		if t.pattern.MatchString(description) {
+			tags = append(tags, hashtagTaxonomy[t.term]...)
+		}
+	}
+	return tags
+}
+
+// termHashtags returns the curated tags for a term, or the term as a tag
+func termHashtags(term string) []string {
+	term = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(term), "#")))
+	if term == "" {
+		return nil
+	}
+	if tags, ok := hashtagTaxonomy[term]; ok {
+		return tags
+	}
+
+	tag := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			return r
+		}
+		return -1
+	}, term)
+	if tag == "" || len([]rune(tag)) > maxGeneratedTagLength {
+		return nil
+	}
+	return []string{"#" + tag}
+}
@@ -0,0 +1,99 @@
+package instagram
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// viewsAPIVersion is the first Graph API version without the impressions
+// metric; Meta replaced it with views
+const viewsAPIVersion = 22
+
+// MediaInsights are the lifetime metrics of a published feed post
+type MediaInsights struct {
+	Reach int `json:"reach"`
+	// Impressions is reported as views on API versions where Meta retired impressions
+	Impressions int `json:"impressions"`
+	Likes       int `json:"likes"`
+	Comments    int `json:"comments"`
+	Saves       int `json:"saves"`
+}
+
+// insightsResponse represents the response from the media insights endpoint
+// Newer API versions report lifetime metrics in total_value instead of values
+type insightsResponse struct {
+	Data []struct {
+		Name   string `json:"name"`
+		Values []struct {
+			Value int `json:"value"`
+		} `json:"values"`
+		TotalValue *struct {
+			Value int `json:"value"`
+		} `json:"total_value,omitempty"`
+	} `json:"data"`
+	Error *struct {
+		Message string `json:"message"`
+		Code    int    `json:"code"`
+	} `json:"error,omitempty"`
+}
+
+// MediaInsights fetches the metrics of a published feed post or carousel
+func (c *Client) MediaInsights(ctx context.Context, mediaID string) (*MediaInsights, error) {
+	if !c.IsConfigured() {
+		return nil, fmt.Errorf("Instagram client not configured")
+	}
+
+	impressionsMetric := "impressions"
+	if apiMajorVersion(c.apiVersion) >= viewsAPIVersion {
+		impressionsMetric = "views"
+	}
+
+	params := url.Values{}
+	params.Set("metric", "reach,likes,comments,saved,"+impressionsMetric)
+	endpoint := fmt.Sprintf("%s/%s/%s/insights?%s", graphAPIBaseURL, c.apiVersion, mediaID, params.Encode())
+
+	var result insightsResponse
+	if err := c.getJSON(ctx, endpoint, c.accessToken, &result); err != nil {
+		return nil, err
+	}
+	if result.Error != nil {
+		return nil, fmt.Errorf("API error: %s (code: %d)", result.Error.Message, result.Error.Code)
+	}
+
+	insights := &MediaInsights{}
+	for _, metric := range result.Data {
+		value := 0
+		if metric.TotalValue != nil {
+			value = metric.TotalValue.Value
+		} else if len(metric.Values) > 0 {
+			value = metric.Values[0].Value
+		}
+
+		switch metric.Name {
+		case "reach":
+			insights.Reach = value
+		case "likes":
+			insights.Likes = value
+		case "comments":
+			insights.Comments = value
+		case "saved":
+			insights.Saves = value
+		case impressionsMetric:
+			insights.Impressions = value
+		}
+	}
+	return insights, nil
+}
+
+// apiMajorVersion parses "v23.0" as 23, returning 0 when it can't
+func apiMajorVersion(version string) int {
+	major, _, _ := strings.Cut(strings.TrimPrefix(version, "v"), ".")
+	n, err := strconv.Atoi(major)
+	if err != nil {
+		return 0
+	}
+	return n
+}
@@ -0,0 +1,109 @@
+package instagram
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+)
+
+// StoryMedia is the content of a story; set either ImageURL or VideoURL
+type StoryMedia struct {
+	ImageURL string
+	VideoURL string
+}
+
+// ReelOptions are optional settings for a reel
+type ReelOptions struct {
+	// CoverURL is an image shown as the reel's cover
+	CoverURL string
+	// ThumbOffsetMs picks the cover frame from the video when CoverURL is empty
+	ThumbOffsetMs int
+	// ShareToFeed also shows the reel in the profile grid and feed
+	ShareToFeed bool
+}
+
+// PublishStory publishes an image or video as a story
+// Stories have no caption; the content itself has to carry the message
+func (c *Client) PublishStory(ctx context.Context, media StoryMedia) (*PublishResult, error) {
+	if !c.IsConfigured() {
+		return &PublishResult{Posted: false, Error: "Instagram client not configured"}, nil
+	}
+	if (media.ImageURL == "") == (media.VideoURL == "") {
+		return &PublishResult{Posted: false, Error: "story needs exactly one of an image or a video URL"}, nil
+	}
+
+	data := url.Values{}
+	data.Set("media_type", "STORIES")
+	isVideo := media.VideoURL != ""
+	if isVideo {
+		data.Set("video_url", media.VideoURL)
+	} else {
+		data.Set("image_url", media.ImageURL)
+	}
+
+	return c.publishSingle(ctx, "story", data, isVideo)
+}
+
+// PublishReel publishes a video as a reel with a caption
+func (c *Client) PublishReel(ctx context.Context, videoURL, caption string, opts ReelOptions) (*PublishResult, error) {
+	if !c.IsConfigured() {
+		return &PublishResult{Posted: false, Error: "Instagram client not configured"}, nil
+	}
+	if videoURL == "" {
+		return &PublishResult{Posted: false, Error: "reel needs a video URL"}, nil
+	}
+
+	data := url.Values{}
+	data.Set("media_type", "REELS")
+	data.Set("video_url", videoURL)
+	data.Set("caption", truncateCaption(caption))
+	data.Set("share_to_feed", strconv.FormatBool(opts.ShareToFeed))
+	if opts.CoverURL != "" {
+		data.Set("cover_url", opts.CoverURL)
+	} else if opts.ThumbOffsetMs > 0 {
+		data.Set("thumb_offset", strconv.Itoa(opts.ThumbOffsetMs))
+	}
+
+	return c.publishSingle(ctx, "reel", data, true)
+}
+
+// publishSingle creates a container from data, waits for it and publishes it
+// Video containers are polled longer since Instagram transcodes them first
+func (c *Client) publishSingle(ctx context.Context, kind string, data url.Values, isVideo bool) (*PublishResult, error) {
+	if refused := c.checkQuota(ctx); refused != nil {
+		return refused, nil
+	}
+
+	// Step 1: Create media container
+	containerID, err := c.createContainer(ctx, data)
+	if err != nil {
+		return &PublishResult{Posted: false, Error: fmt.Sprintf("failed to create %s container: %v", kind, err)}, nil
+	}
+	log.Printf("Instagram: Created %s container: %s", kind, containerID)
+
+	// Step 2: Poll for container status until FINISHED
+	wait := c.waitForContainerReady
+	if isVideo {
+		wait = c.waitForVideoContainerReady
+	}
+	if err := wait(ctx, containerID); err != nil {
+		return &PublishResult{Posted: false, ContainerID: containerID, Error: fmt.Sprintf("container not ready: %v", err)}, nil
+	}
+	log.Printf("Instagram: %s container ready for publishing", kind)
+
+	// Step 3: Publish the container
+	mediaID, err := c.publishContainer(ctx, containerID)
+	if err != nil {
+		return &PublishResult{Posted: false, ContainerID: containerID, Error: fmt.Sprintf("failed to publish: %v", err)}, nil
+	}
+	log.Printf("Instagram: Successfully published %s with media ID: %s", kind, mediaID)
+
+	return &PublishResult{
+		MediaID:     mediaID,
+		Posted:      true,
+		ContainerID: containerID,
+		Permalink:   c.permalink(ctx, mediaID),
+	}, nil
+}
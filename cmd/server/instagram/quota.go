@@ -0,0 +1,96 @@
+package instagram
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+)
+
+// PublishingQuota is the account's rolling publishing limit
+// Carousels count as a single post
+type PublishingQuota struct {
+	Used     int           `json:"used"`
+	Total    int           `json:"total"`
+	Duration time.Duration `json:"-"`
+	// Remaining is Total minus Used, never negative
+	Remaining int `json:"remaining"`
+	// WindowHours is Duration in hours, for JSON
+	WindowHours int `json:"window_hours"`
+}
+
+// Exhausted reports whether no posts are left in the current window
+func (q *PublishingQuota) Exhausted() bool {
+	return q.Remaining <= 0
+}
+
+// quotaResponse represents the response from the content_publishing_limit endpoint
+type quotaResponse struct {
+	Data []struct {
+		QuotaUsage int `json:"quota_usage"`
+		Config     struct {
+			QuotaTotal    int   `json:"quota_total"`
+			QuotaDuration int64 `json:"quota_duration"`
+		} `json:"config"`
+	} `json:"data"`
+	Error *struct {
+		Message string `json:"message"`
+		Code    int    `json:"code"`
+	} `json:"error,omitempty"`
+}
+
+// PublishingQuota returns how many posts the account published in the
+// current window and how many it may publish
+func (c *Client) PublishingQuota(ctx context.Context) (*PublishingQuota, error) {
+	if !c.IsConfigured() {
+		return nil, fmt.Errorf("Instagram client not configured")
+	}
+
+	params := url.Values{}
+	params.Set("fields", "quota_usage,config")
+	endpoint := fmt.Sprintf("%s/%s/%s/content_publishing_limit?%s", graphAPIBaseURL, c.apiVersion, c.userID, params.Encode())
+
+	var result quotaResponse
+	if err := c.getJSON(ctx, endpoint, c.accessToken, &result); err != nil {
+		return nil, err
+	}
+	if result.Error != nil {
+		return nil, fmt.Errorf("API error: %s (code: %d)", result.Error.Message, result.Error.Code)
+	}
+	if len(result.Data) == 0 {
+		return nil, fmt.Errorf("no publishing limit returned")
+	}
+
+	data := result.Data[0]
+	quota := &PublishingQuota{
+		Used:     data.QuotaUsage,
+		Total:    data.Config.QuotaTotal,
+		Duration: time.Duration(data.Config.QuotaDuration) * time.Second,
+	}
+	quota.Remaining = max(quota.Total-quota.Used, 0)
+	quota.WindowHours = int(quota.Duration / time.Hour)
+	return quota, nil
+}
+
+// checkQuota refuses to publish when the publishing quota is exhausted, so
+// posting stops before any container is created
+// Returns nil when publishing may go ahead; if the quota can't be read the
+// post is attempted and Instagram enforces the limit itself
+func (c *Client) checkQuota(ctx context.Context) *PublishResult {
+	quota, err := c.PublishingQuota(ctx)
+	if err != nil {
+		log.Printf("Instagram: Failed to check publishing quota, publishing anyway: %v", err)
+		return nil
+	}
+	if !quota.Exhausted() {
+		return nil
+	}
+
+	log.Printf("Instagram: Publishing quota exhausted (%d/%d)", quota.Used, quota.Total)
+	return &PublishResult{
+		Posted:         false,
+		QuotaExhausted: true,
+		Error:          fmt.Sprintf("publishing quota exhausted: %d of %d posts used in the last %d hours", quota.Used, quota.Total, quota.WindowHours),
+	}
+}
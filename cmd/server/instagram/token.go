@@ -0,0 +1,179 @@
+package instagram
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// TokenInfo describes an access token as reported by the debug_token endpoint
+type TokenInfo struct {
+	Valid bool
+	// ExpiresAt is zero for tokens that never expire (such as page tokens)
+	ExpiresAt time.Time
+	// DataAccessExpiresAt is when the user's data access grant lapses, after
+	// which even a refreshed token stops working until they log in again
+	DataAccessExpiresAt time.Time
+	Scopes              []string
+	// Error explains why an invalid token was rejected
+	Error string
+}
+
+// RefreshedToken is a newly issued long-lived token
+type RefreshedToken struct {
+	AccessToken string
+	// ExpiresAt is zero when the response carried no lifetime
+	ExpiresAt time.Time
+}
+
+// debugTokenResponse represents the response from the debug_token endpoint
+type debugTokenResponse struct {
+	Data *struct {
+		IsValid             bool     `json:"is_valid"`
+		ExpiresAt           int64    `json:"expires_at"`
+		DataAccessExpiresAt int64    `json:"data_access_expires_at"`
+		Scopes              []string `json:"scopes"`
+		Error               *struct {
+			Message string `json:"message"`
+			Code    int    `json:"code"`
+		} `json:"error,omitempty"`
+	} `json:"data"`
+	Error *struct {
+		Message string `json:"message"`
+		Code    int    `json:"code"`
+	} `json:"error,omitempty"`
+}
+
+// refreshTokenResponse represents the response from exchanging a token
+type refreshTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+	Error       *struct {
+		Message string `json:"message"`
+		Code    int    `json:"code"`
+	} `json:"error,omitempty"`
+}
+
+// AppAccessToken returns the app access token used to inspect tokens
+func AppAccessToken(appID, appSecret string) string {
+	return appID + "|" + appSecret
+}
+
+// DebugToken inspects the client's access token
+// inspector is an app access token (see AppAccessToken), or the token itself
+// when the app credentials are unavailable
+func (c *Client) DebugToken(ctx context.Context, inspector string) (*TokenInfo, error) {
+	params := url.Values{}
+	params.Set("input_token", c.accessToken)
+	endpoint := fmt.Sprintf("%s/%s/debug_token?%s", graphAPIBaseURL, c.apiVersion, params.Encode())
+
+	var result debugTokenResponse
+	if err := c.getJSON(ctx, endpoint, inspector, &result); err != nil {
+		return nil, err
+	}
+	if result.Error != nil {
+		return nil, fmt.Errorf("API error: %s (code: %d)", result.Error.Message, result.Error.Code)
+	}
+	if result.Data == nil {
+		return nil, fmt.Errorf("no token data returned")
+	}
+
+	info := &TokenInfo{
+		Valid:               result.Data.IsValid,
+		ExpiresAt:           unixTime(result.Data.ExpiresAt),
+		DataAccessExpiresAt: unixTime(result.Data.DataAccessExpiresAt),
+		Scopes:              result.Data.Scopes,
+	}
+	if result.Data.Error != nil {
+		info.Error = fmt.Sprintf("%s (code: %d)", result.Data.Error.Message, result.Data.Error.Code)
+	}
+	return info, nil
+}
+
+// RefreshToken exchanges the client's long-lived token for a new one with a
+// fresh 60-day lifetime. The old token keeps working until it expires.
+func (c *Client) RefreshToken(ctx context.Context, appID, appSecret string) (*RefreshedToken, error) {
+	params := url.Values{}
+	params.Set("grant_type", "fb_exchange_token")
+	params.Set("client_id", appID)
+	params.Set("client_secret", appSecret)
+	params.Set("fb_exchange_token", c.accessToken)
+	endpoint := fmt.Sprintf("%s/%s/oauth/access_token?%s", graphAPIBaseURL, c.apiVersion, params.Encode())
+
+	// The exchange authenticates with the app secret in the query, not a bearer token
+	var result refreshTokenResponse
+	if err := c.getJSON(ctx, endpoint, "", &result); err != nil {
+		return nil, err
+	}
+	if result.Error != nil {
+		return nil, fmt.Errorf("API error: %s (code: %d)", result.Error.Message, result.Error.Code)
+	}
+	if result.AccessToken == "" {
+		return nil, fmt.Errorf("no access token returned")
+	}
+
+	refreshed := &RefreshedToken{AccessToken: result.AccessToken}
+	if result.ExpiresIn > 0 {
+		refreshed.ExpiresAt = time.Now().Add(time.Duration(result.ExpiresIn) * time.Second)
+	}
+	return refreshed, nil
+}
+
+// getJSON sends a GET request authenticated with token and decodes the JSON
+// response into out. Graph API errors come back as JSON with a non-200
+// status, so the body is decoded regardless of status and callers check its
+// error field
+func (c *Client) getJSON(ctx context.Context, endpoint, token string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	body, err := c.do(req, token)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+// do sends req with token as a bearer token, keeping it out of URLs and logs,
+// and returns the response body
+func (c *Client) do(req *http.Request, token string) ([]byte, error) {
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		// The URL may carry the app secret during token exchange; keep it out of the error
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	return body, nil
+}
+
+// unixTime converts a Unix timestamp, treating 0 as "never"
+func unixTime(sec int64) time.Time {
+	if sec <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0).UTC()
+}
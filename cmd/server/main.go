@@ -13,7 +13,11 @@ import (
 	"github.com/joho/godotenv"
 	"startupdose.com/cmd/server/config"
 	"startupdose.com/cmd/server/database"
+	"startupdose.com/cmd/server/igtoken"
+	"startupdose.com/cmd/server/insights"
 	"startupdose.com/cmd/server/router"
+	"startupdose.com/cmd/server/screenshot"
+	"startupdose.com/cmd/server/storage"
 )
 
 func main() {
@@ -32,9 +36,43 @@ func main() {
 		// This allows the server to run without Supabase if needed
 	}
 
+	// Initialize object storage
+	if err := storage.Init(cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize object storage: %v\n", err)
+		// Asset uploads are skipped and generated companies fall back to AI-provided images
+	}
+
+	// Check the screenshot provider now so a missing browser or API key shows
+	// up at startup instead of on the first generation
+	if _, err := screenshot.New(screenshot.Config{
+		Provider:            cfg.ScreenshotProvider,
+		ScreenshotOneAPIKey: cfg.ScreenshotOneAPIKey,
+		ChromePath:          cfg.ChromePath,
+	}); err != nil {
+		fmt.Fprintf(os.Stderr, "Screenshot provider unavailable: %v\n", err)
+		// Generated companies fall back to AI-provided images
+	}
+
 	// Ensure cleanup on exit
 	defer database.Close()
 
+	// Run maintenance commands such as "gc assets" instead of serving
+	if len(os.Args) > 1 {
+		code := runCommand(cfg, os.Args[1:])
+		database.Close()
+		os.Exit(code)
+	}
+
+	// Background jobs stop when the server shuts down
+	bgCtx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+
+	// Keep the Instagram token fresh; the first check inspects it right away
+	go igtoken.Init(cfg).Run(bgCtx)
+
+	// Collect metrics of recent Instagram posts
+	go insights.Run(bgCtx, insights.NewOptions(cfg))
+
 	// Create HTTP server
 	mux := router.Setup(cfg)
 	srv := &http.Server{
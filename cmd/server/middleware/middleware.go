@@ -71,6 +71,11 @@ func (rw *responseWriter) WriteHeader(statusCode int) {
 	rw.ResponseWriter.WriteHeader(statusCode)
 }
 
+// Unwrap lets http.ResponseController reach the underlying writer
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 // APIKeyAuthMiddleware validates the x-api-key header against the configured API key
 // Returns a middleware function that can be used to wrap specific handlers
 func APIKeyAuthMiddleware(apiKey string) func(http.HandlerFunc) http.HandlerFunc {
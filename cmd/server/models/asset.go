@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// Asset links a company to a stored, content-addressed object
+type Asset struct {
+	ID        string    `json:"id,omitempty"`
+	CompanyID string    `json:"company_id"`
+	Hash      string    `json:"hash"`
+	Key       string    `json:"key"`
+	MimeType  string    `json:"mime_type"`
+	SizeBytes int       `json:"size_bytes"`
+	Width     int       `json:"width,omitempty"`
+	Height    int       `json:"height,omitempty"`
+	Source    string    `json:"source"`
+	SourceURL string    `json:"source_url,omitempty"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// CompanyCandidate represents a generated company that was not selected
+// It is kept as a backlog entry that can be featured later
+type CompanyCandidate struct {
+	ID          string                 `json:"id,omitempty"`
+	Name        string                 `json:"name"`
+	Slug        string                 `json:"slug"`
+	Website     string                 `json:"website"`
+	CoverImage  string                 `json:"cover_image"`
+	Description string                 `json:"description"`
+	Appeal      string                 `json:"appeal"`
+	Twitter     string                 `json:"twitter,omitempty"`
+	LinkedIn    string                 `json:"linkedin,omitempty"`
+	Facebook    string                 `json:"facebook,omitempty"`
+	Instagram   string                 `json:"instagram,omitempty"`
+	Score       float64                `json:"score"`
+	Checks      map[string]bool        `json:"checks"`
+	Status      string                 `json:"status"`
+	Params      map[string]interface{} `json:"generation_params,omitempty"`
+	CreatedAt   time.Time              `json:"created_at,omitempty"`
+}
+
+// Candidate statuses
+const (
+	CandidateStatusBacklog   = "backlog"
+	CandidateStatusDuplicate = "duplicate"
+)
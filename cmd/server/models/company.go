@@ -4,19 +4,36 @@ import "time"
 
 // Company represents a company entity from the database
 type Company struct {
-	ID          string     `json:"id"`
-	Name        string     `json:"name"`
-	Slug        string     `json:"slug"`
-	Description string     `json:"description"`
-	Excerpt     string     `json:"excerpt"`
-	Appeal      string     `json:"appeal"`
-	Website     string     `json:"website"`
-	CoverImage  string     `json:"cover_image"`
-	Twitter     *string    `json:"twitter"`
-	LinkedIn    *string    `json:"linkedin"`
-	Facebook    *string    `json:"facebook"`
-	Instagram   *string    `json:"instagram"`
-	PublishedAt *time.Time `json:"published_at"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
+	ID            string       `json:"id"`
+	Name          string       `json:"name"`
+	Slug          string       `json:"slug"`
+	Description   string       `json:"description"`
+	Excerpt       string       `json:"excerpt"`
+	Appeal        string       `json:"appeal"`
+	Category      string       `json:"category"`
+	Keywords      []string     `json:"keywords"`
+	Website       string       `json:"website"`
+	WebsiteScheme string       `json:"website_scheme"`
+	WebsiteLink   string       `json:"website_link"`
+	Domain        string       `json:"domain"`
+	CoverImage    string       `json:"cover_image"`
+	CoverImages   []CoverImage `json:"cover_images"`
+	SquareCard    *string      `json:"square_card_image"`
+	PortraitCard  *string      `json:"portrait_card_image"`
+	Twitter       *string      `json:"twitter"`
+	LinkedIn      *string      `json:"linkedin"`
+	Facebook      *string      `json:"facebook"`
+	Instagram     *string      `json:"instagram"`
+	PublishedAt   *time.Time   `json:"published_at"`
+	CreatedAt     time.Time    `json:"created_at"`
+	UpdatedAt     time.Time    `json:"updated_at"`
+}
+
+// CoverImage is one responsive rendition of a company's cover image
+// The frontend uses these to build srcset attributes
+type CoverImage struct {
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	Format string `json:"format"`
 }
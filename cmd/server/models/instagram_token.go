@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// InstagramToken represents the stored access token for an Instagram account
+type InstagramToken struct {
+	ID                  string     `json:"id,omitempty"`
+	IGUserID            string     `json:"ig_user_id"`
+	AccessToken         string     `json:"access_token"`
+	ExpiresAt           *time.Time `json:"expires_at"`
+	DataAccessExpiresAt *time.Time `json:"data_access_expires_at"`
+	Scopes              []string   `json:"scopes"`
+	IsValid             bool       `json:"is_valid"`
+	LastRefreshedAt     *time.Time `json:"last_refreshed_at"`
+	LastCheckedAt       *time.Time `json:"last_checked_at"`
+	LastError           *string    `json:"last_error"`
+	CreatedAt           time.Time  `json:"created_at,omitempty"`
+	UpdatedAt           time.Time  `json:"updated_at,omitempty"`
+}
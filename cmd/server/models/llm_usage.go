@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// LLMUsage represents a single recorded LLM call and its cost
+type LLMUsage struct {
+	ID               string    `json:"id,omitempty"`
+	Model            string    `json:"model"`
+	Purpose          string    `json:"purpose"`
+	PromptTokens     int       `json:"prompt_tokens"`
+	CompletionTokens int       `json:"completion_tokens"`
+	TotalTokens      int       `json:"total_tokens"`
+	CostUSD          float64   `json:"cost_usd"`
+	CreatedAt        time.Time `json:"created_at,omitempty"`
+}
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// SocialPost records publishing a company to one social channel
+type SocialPost struct {
+	ID          string     `json:"id"`
+	CompanyID   string     `json:"company_id"`
+	Channel     string     `json:"channel"`
+	Status      string     `json:"status"`
+	ContainerID *string    `json:"container_id"`
+	MediaID     *string    `json:"media_id"`
+	Permalink   *string    `json:"permalink"`
+	Error       *string    `json:"error"`
+	Attempts    int        `json:"attempts"`
+	ClaimedBy   *string    `json:"claimed_by"`
+	ClaimedAt   time.Time  `json:"claimed_at"`
+	PostedAt    *time.Time `json:"posted_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// Social channels
+const (
+	SocialChannelInstagram      = "instagram"
+	SocialChannelInstagramStory = "instagram_story"
+)
+
+// Social post statuses
+const (
+	SocialPostStatusPosting = "posting"
+	SocialPostStatusPosted  = "posted"
+	SocialPostStatusFailed  = "failed"
+)
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// SocialPostInsight is one snapshot of a posted social post's metrics
+type SocialPostInsight struct {
+	ID           string    `json:"id"`
+	SocialPostID string    `json:"social_post_id"`
+	CompanyID    string    `json:"company_id"`
+	MediaID      string    `json:"media_id"`
+	Reach        int       `json:"reach"`
+	Impressions  int       `json:"impressions"`
+	Likes        int       `json:"likes"`
+	Comments     int       `json:"comments"`
+	Saves        int       `json:"saves"`
+	FetchedAt    time.Time `json:"fetched_at"`
+}
+
+// LeaderboardEntry is the latest snapshot of a post with the company it features
+type LeaderboardEntry struct {
+	SocialPostID string     `json:"social_post_id"`
+	CompanyID    string     `json:"company_id"`
+	CompanyName  string     `json:"company_name"`
+	CompanySlug  string     `json:"company_slug"`
+	Channel      string     `json:"channel"`
+	MediaID      string     `json:"media_id"`
+	Permalink    *string    `json:"permalink"`
+	PostedAt     *time.Time `json:"posted_at"`
+	Reach        int        `json:"reach"`
+	Impressions  int        `json:"impressions"`
+	Likes        int        `json:"likes"`
+	Comments     int        `json:"comments"`
+	Saves        int        `json:"saves"`
+	Engagement   int        `json:"engagement"`
+	FetchedAt    time.Time  `json:"fetched_at"`
+}
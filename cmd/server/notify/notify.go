@@ -0,0 +1,59 @@
+// Package notify sends operational alerts, such as a failing Instagram token
+// refresh, to a chat webhook.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// Notifier posts alerts to a webhook
+// The payload is {"text": "..."}, which Slack, Mattermost and Discord-compatible
+// (via /slack) incoming webhooks all accept
+type Notifier struct {
+	webhookURL string
+	httpClient *http.Client
+}
+
+// New creates a Notifier; with an empty URL alerts are only logged
+func New(webhookURL string) *Notifier {
+	return &Notifier{
+		webhookURL: webhookURL,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Notify logs the message and posts it to the webhook if one is configured
+func (n *Notifier) Notify(ctx context.Context, message string) error {
+	log.Printf("NOTIFY: %s\n", message)
+	if n == nil || n.webhookURL == "" {
+		return nil
+	}
+
+	payload, err := json.Marshal(map[string]string{"text": "[startup-dose-api] " + message})
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.webhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
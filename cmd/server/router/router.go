@@ -5,6 +5,7 @@ import (
 	"startupdose.com/cmd/server/config"
 	"startupdose.com/cmd/server/handler"
 	"startupdose.com/cmd/server/middleware"
+	"startupdose.com/cmd/server/storage"
 )
 
 // Setup configures and returns the HTTP router with all routes and middleware
@@ -17,11 +18,24 @@ func Setup(cfg *config.Config) http.Handler {
 	// Register public handlers
 	mux.HandleFunc("GET /posts/1", handler.PostsHandler)
 	mux.HandleFunc("GET /healthz", handler.HealthzHandler)
+	mux.HandleFunc("GET /readyz", handler.ReadyzHandler)
 	mux.HandleFunc("GET /companies/latest", handler.CompanyLatestHandler)
+	mux.HandleFunc("GET /companies/{slug}", handler.CompanyBySlugHandler)
 	mux.HandleFunc("GET /debug/companies", handler.DebugCompaniesHandler)
 
+	// Serve locally stored assets when using the local storage backend
+	if assets := storage.AssetHandler(); assets != nil {
+		mux.Handle("GET "+storage.AssetsPath, assets)
+	}
+
 	// Register protected handlers (require API key)
 	mux.HandleFunc("POST /companies/generate", apiKeyAuth(handler.GenerateCompaniesHandler))
+	mux.HandleFunc("GET /admin/llm/spend", apiKeyAuth(handler.LLMSpendHandler))
+	mux.HandleFunc("POST /admin/assets/gc", apiKeyAuth(handler.AssetGCHandler))
+	mux.HandleFunc("GET /admin/instagram/status", apiKeyAuth(handler.InstagramStatusHandler))
+	mux.HandleFunc("POST /companies/{id}/social/instagram/retry", apiKeyAuth(handler.InstagramRetryHandler))
+	mux.HandleFunc("GET /companies/{id}/stats", apiKeyAuth(handler.CompanyStatsHandler))
+	mux.HandleFunc("GET /companies/leaderboard", apiKeyAuth(handler.LeaderboardHandler))
 
 	// Wrap with middleware (order matters: outer wraps inner)
 	var handlerWrapper http.Handler = mux
@@ -0,0 +1,345 @@
+package screenshot
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"golang.org/x/net/websocket"
+
+	"startupdose.com/cmd/server/fetch"
+)
+
+const (
+	defaultChromePath    = "chromium-browser"
+	chromeStartTimeout   = 15 * time.Second
+	chromeCaptureTimeout = 45 * time.Second
+)
+
+// Chrome captures screenshots with a local headless Chrome/Chromium instance,
+// driven through the DevTools protocol
+// BlockCookieBanners is not supported; use HiddenSelectors to hide known banners
+type Chrome struct {
+	path string
+}
+
+// NewChrome creates a new Chrome provider using the browser binary at path
+func NewChrome(path string) *Chrome {
+	if path == "" {
+		path = defaultChromePath
+	}
+	return &Chrome{path: path}
+}
+
+// Name returns the provider name
+func (c *Chrome) Name() string {
+	return ProviderChrome
+}
+
+// Capture launches a fresh headless browser, loads websiteURL and captures the viewport
+func (c *Chrome) Capture(ctx context.Context, websiteURL string, opts Options) ([]byte, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, chromeCaptureTimeout)
+	defer cancel()
+
+	if err := checkPublicURL(ctx, websiteURL); err != nil {
+		return nil, err
+	}
+
+	proxy, err := startFilteringProxy()
+	if err != nil {
+		return nil, err
+	}
+	defer proxy.Close()
+
+	profileDir, err := os.MkdirTemp("", "startupdose-chrome-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create browser profile dir: %w", err)
+	}
+	defer os.RemoveAll(profileDir)
+
+	cmd := exec.CommandContext(ctx, c.path,
+		"--headless=new",
+		"--disable-gpu",
+		"--no-sandbox", // Required when running as root inside the container
+		"--hide-scrollbars",
+		"--mute-audio",
+		"--no-first-run",
+		"--remote-debugging-port=0",
+		"--remote-allow-origins=*",
+		"--user-data-dir="+profileDir,
+		// Route every request through the filtering proxy, including loopback
+		// ones Chrome would otherwise send directly
+		"--proxy-server="+proxy.URL(),
+		"--proxy-bypass-list=<-loopback>",
+		// WebRTC would otherwise send UDP around the proxy
+		"--force-webrtc-ip-handling-policy=disable_non_proxied_udp",
+		"about:blank",
+	)
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to attach to browser stderr: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start browser %s: %w", c.path, err)
+	}
+	defer func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	}()
+
+	browserURL, err := waitForDevTools(stderr)
+	if err != nil {
+		return nil, err
+	}
+
+	pageURL, err := findPageTarget(ctx, browserURL)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := websocket.Dial(pageURL, "", "http://127.0.0.1/")
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to browser: %w", err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	session := &devToolsSession{conn: conn, events: map[string]bool{}}
+	return session.capture(ctx, websiteURL, opts)
+}
+
+// checkPublicURL rejects URLs that aren't http(s) or whose host resolves to a
+// non-public address, so obviously internal targets fail before the browser
+// starts; the proxy still checks every connection the page makes
+func checkPublicURL(ctx context.Context, websiteURL string) error {
+	u, err := url.Parse(websiteURL)
+	if err != nil {
+		return fmt.Errorf("invalid website URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("unsupported URL scheme %q", u.Scheme)
+	}
+	host := u.Hostname()
+	if host == "" {
+		return fmt.Errorf("website URL has no host")
+	}
+
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", host, err)
+	}
+	for _, addr := range addrs {
+		addr = addr.Unmap()
+		if !fetch.IsPublicAddr(addr) {
+			return fmt.Errorf("%w: %s resolves to %s", fetch.ErrBlockedAddress, host, addr)
+		}
+	}
+	return nil
+}
+
+// waitForDevTools reads the browser's stderr until it prints its DevTools URL
+func waitForDevTools(stderr io.Reader) (*url.URL, error) {
+	found := make(chan string, 1)
+	go func() {
+		scanner := bufio.NewScanner(stderr)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if i := strings.Index(line, "DevTools listening on "); i != -1 {
+				found <- strings.TrimSpace(line[i+len("DevTools listening on "):])
+				break
+			}
+		}
+		// Keep draining so the browser never blocks on a full pipe
+		_, _ = io.Copy(io.Discard, stderr)
+	}()
+
+	select {
+	case raw := <-found:
+		u, err := url.Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid DevTools URL %q: %w", raw, err)
+		}
+		return u, nil
+	case <-time.After(chromeStartTimeout):
+		return nil, fmt.Errorf("browser did not start within %v", chromeStartTimeout)
+	}
+}
+
+// findPageTarget returns the DevTools websocket URL of the browser's initial page
+func findPageTarget(ctx context.Context, browserURL *url.URL) (string, error) {
+	listURL := fmt.Sprintf("http://%s/json/list", browserURL.Host)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, listURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create target list request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to list browser targets: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var targets []struct {
+		Type                 string `json:"type"`
+		WebSocketDebuggerURL string `json:"webSocketDebuggerUrl"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&targets); err != nil {
+		return "", fmt.Errorf("failed to parse browser targets: %w", err)
+	}
+
+	for _, t := range targets {
+		if t.Type == "page" && t.WebSocketDebuggerURL != "" {
+			return t.WebSocketDebuggerURL, nil
+		}
+	}
+	return "", fmt.Errorf("browser has no page target")
+}
+
+// devToolsSession is a minimal, sequential DevTools protocol client for one page
+type devToolsSession struct {
+	conn   *websocket.Conn
+	nextID int
+	events map[string]bool
+}
+
+// devToolsMessage is either a command response or an event
+type devToolsMessage struct {
+	ID     int             `json:"id"`
+	Method string          `json:"method"`
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// capture navigates to websiteURL and returns the screenshot bytes
+func (s *devToolsSession) capture(ctx context.Context, websiteURL string, opts Options) ([]byte, error) {
+	if _, err := s.call("Emulation.setDeviceMetricsOverride", map[string]interface{}{
+		"width":             opts.ViewportWidth,
+		"height":            opts.ViewportHeight,
+		"deviceScaleFactor": opts.DeviceScaleFactor,
+		"mobile":            false,
+	}); err != nil {
+		return nil, err
+	}
+	if _, err := s.call("Page.enable", nil); err != nil {
+		return nil, err
+	}
+
+	result, err := s.call("Page.navigate", map[string]interface{}{"url": websiteURL})
+	if err != nil {
+		return nil, err
+	}
+	var nav struct {
+		ErrorText string `json:"errorText"`
+	}
+	if err := json.Unmarshal(result, &nav); err == nil && nav.ErrorText != "" {
+		return nil, fmt.Errorf("failed to load %s: %s", websiteURL, nav.ErrorText)
+	}
+
+	if err := s.waitForEvent("Page.loadEventFired"); err != nil {
+		return nil, err
+	}
+
+	if len(opts.HiddenSelectors) > 0 {
+		css := strings.Join(opts.HiddenSelectors, ", ") + " { display: none !important; }"
+		script := fmt.Sprintf(`(() => { const s = document.createElement("style"); s.textContent = %q; document.documentElement.appendChild(s); })()`, css)
+		if _, err := s.call("Runtime.evaluate", map[string]interface{}{"expression": script}); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.Delay > 0 {
+		select {
+		case <-time.After(opts.Delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	params := map[string]interface{}{"format": opts.Format}
+	if opts.Format == FormatJPEG {
+		params["quality"] = 90
+	}
+	result, err = s.call("Page.captureScreenshot", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var shot struct {
+		Data string `json:"data"`
+	}
+	if err := json.Unmarshal(result, &shot); err != nil {
+		return nil, fmt.Errorf("failed to parse screenshot response: %w", err)
+	}
+	data, err := base64.StdEncoding.DecodeString(shot.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode screenshot data: %w", err)
+	}
+	return data, nil
+}
+
+// call sends a command and waits for its response, recording events seen meanwhile
+func (s *devToolsSession) call(method string, params interface{}) (json.RawMessage, error) {
+	s.nextID++
+	id := s.nextID
+	if err := websocket.JSON.Send(s.conn, map[string]interface{}{
+		"id":     id,
+		"method": method,
+		"params": params,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to send %s: %w", method, err)
+	}
+
+	for {
+		msg, err := s.read()
+		if err != nil {
+			return nil, fmt.Errorf("failed waiting for %s: %w", method, err)
+		}
+		if msg.ID != id {
+			continue
+		}
+		if msg.Error != nil {
+			return nil, fmt.Errorf("%s failed: %s", method, msg.Error.Message)
+		}
+		return msg.Result, nil
+	}
+}
+
+// waitForEvent blocks until the named event has been received
+func (s *devToolsSession) waitForEvent(method string) error {
+	for !s.events[method] {
+		if _, err := s.read(); err != nil {
+			return fmt.Errorf("failed waiting for %s: %w", method, err)
+		}
+	}
+	return nil
+}
+
+// read receives one message and records it if it is an event
+func (s *devToolsSession) read() (*devToolsMessage, error) {
+	var msg devToolsMessage
+	if err := websocket.JSON.Receive(s.conn, &msg); err != nil {
+		return nil, err
+	}
+	if msg.Method != "" {
+		s.events[msg.Method] = true
+	}
+	return &msg, nil
+}
@@ -0,0 +1,72 @@
+package screenshot
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+)
+
+// Fake renders a deterministic placeholder image derived from the URL
+// It makes no network calls and is intended for local development and tests
+type Fake struct{}
+
+// NewFake creates a new Fake provider
+func NewFake() *Fake {
+	return &Fake{}
+}
+
+// Name returns the provider name
+func (f *Fake) Name() string {
+	return ProviderFake
+}
+
+// Capture returns an image whose colors depend only on websiteURL and whose
+// size matches the viewport multiplied by the device scale factor
+func (f *Fake) Capture(ctx context.Context, websiteURL string, opts Options) ([]byte, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	width := int(float64(opts.ViewportWidth) * opts.DeviceScaleFactor)
+	height := int(float64(opts.ViewportHeight) * opts.DeviceScaleFactor)
+	sum := sha256.Sum256([]byte(websiteURL))
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	// Fake "header" bar and "content" blocks so the image isn't a flat color
+	header := height / 8
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			var c color.RGBA
+			switch {
+			case y < header:
+				c = color.RGBA{sum[0], sum[1], sum[2], 255}
+			case (x/(width/8+1)+y/(height/6+1))%2 == 0:
+				c = color.RGBA{sum[3], sum[4], sum[5], 255}
+			default:
+				c = color.RGBA{sum[6], sum[7], sum[8], 255}
+			}
+			img.SetRGBA(x, y, c)
+		}
+	}
+
+	var buf bytes.Buffer
+	var err error
+	if opts.Format == FormatJPEG {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: 90})
+	} else {
+		err = png.Encode(&buf, img)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode fake screenshot: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
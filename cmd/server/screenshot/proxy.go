@@ -0,0 +1,153 @@
+package screenshot
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"time"
+
+	"startupdose.com/cmd/server/fetch"
+)
+
+// hopHeaders are connection-level headers a proxy must not forward
+var hopHeaders = []string{
+	"Connection",
+	"Keep-Alive",
+	"Proxy-Authenticate",
+	"Proxy-Authorization",
+	"Proxy-Connection",
+	"Te",
+	"Trailer",
+	"Transfer-Encoding",
+	"Upgrade",
+}
+
+// filteringProxy is a local HTTP proxy the browser sends all of its traffic
+// through. It connects with fetch's dialer, so redirects, subresources and
+// hostnames that resolve differently inside the browser can never reach a
+// private, loopback or link-local address.
+type filteringProxy struct {
+	listener  net.Listener
+	server    *http.Server
+	dialer    *net.Dialer
+	transport *http.Transport
+}
+
+// startFilteringProxy listens on a random loopback port and serves until closed
+func startFilteringProxy() (*filteringProxy, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to start browser proxy: %w", err)
+	}
+
+	dialer := fetch.NewDialer()
+	p := &filteringProxy{
+		listener: listener,
+		dialer:   dialer,
+		transport: &http.Transport{
+			Proxy:                 nil,
+			DialContext:           dialer.DialContext,
+			TLSHandshakeTimeout:   5 * time.Second,
+			ResponseHeaderTimeout: chromeCaptureTimeout,
+			IdleConnTimeout:       30 * time.Second,
+		},
+	}
+	p.server = &http.Server{
+		Handler:           p,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		if err := p.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			log.Printf("WARNING: Browser proxy stopped: %v\n", err)
+		}
+	}()
+	return p, nil
+}
+
+// URL returns the proxy address in the form Chrome's --proxy-server expects
+func (p *filteringProxy) URL() string {
+	return "http://" + p.listener.Addr().String()
+}
+
+// Close stops the proxy and drops open tunnels
+func (p *filteringProxy) Close() {
+	_ = p.server.Close()
+	p.transport.CloseIdleConnections()
+}
+
+// ServeHTTP tunnels CONNECT requests (HTTPS, WebSockets) and forwards plain HTTP
+func (p *filteringProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodConnect {
+		p.tunnel(w, r)
+		return
+	}
+	p.forward(w, r)
+}
+
+// tunnel connects to the CONNECT target and copies bytes in both directions
+func (p *filteringProxy) tunnel(w http.ResponseWriter, r *http.Request) {
+	upstream, err := p.dialer.DialContext(r.Context(), "tcp", r.Host)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		upstream.Close()
+		http.Error(w, "tunneling not supported", http.StatusInternalServerError)
+		return
+	}
+	client, _, err := hijacker.Hijack()
+	if err != nil {
+		upstream.Close()
+		return
+	}
+	if _, err := io.WriteString(client, "HTTP/1.1 200 Connection Established\r\n\r\n"); err != nil {
+		client.Close()
+		upstream.Close()
+		return
+	}
+
+	go func() {
+		_, _ = io.Copy(upstream, client)
+		upstream.Close()
+	}()
+	_, _ = io.Copy(client, upstream)
+	client.Close()
+}
+
+// forward sends a plain HTTP request upstream; redirects are returned to the
+// browser, which requests the new location through the proxy again
+func (p *filteringProxy) forward(w http.ResponseWriter, r *http.Request) {
+	if !r.URL.IsAbs() || r.URL.Scheme != "http" {
+		http.Error(w, "only absolute http URLs can be proxied", http.StatusBadRequest)
+		return
+	}
+
+	out := r.Clone(r.Context())
+	out.RequestURI = ""
+	for _, h := range hopHeaders {
+		out.Header.Del(h)
+	}
+
+	resp, err := p.transport.RoundTrip(out)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+
+	for _, h := range hopHeaders {
+		resp.Header.Del(h)
+	}
+	for key, values := range resp.Header {
+		for _, v := range values {
+			w.Header().Add(key, v)
+		}
+	}
+	w.WriteHeader(resp.StatusCode)
+	_, _ = io.Copy(w, resp.Body)
+}
@@ -0,0 +1,116 @@
+package screenshot
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"time"
+)
+
+// Provider names accepted by New
+const (
+	ProviderScreenshotOne = "screenshotone"
+	ProviderChrome        = "chrome"
+	ProviderFake          = "fake"
+)
+
+// Image formats supported by all providers
+const (
+	FormatPNG  = "png"
+	FormatJPEG = "jpeg"
+)
+
+// Options controls how a page is captured
+type Options struct {
+	ViewportWidth     int
+	ViewportHeight    int
+	DeviceScaleFactor float64
+	Format            string
+	// Delay waits after the page has loaded before capturing
+	Delay time.Duration
+	// HiddenSelectors are CSS selectors hidden before capturing (chat widgets, banners)
+	HiddenSelectors []string
+	// BlockCookieBanners asks the provider to remove cookie consent banners where supported
+	BlockCookieBanners bool
+}
+
+// DefaultOptions returns the options used for company cover screenshots
+func DefaultOptions() Options {
+	return Options{
+		ViewportWidth:      1280,
+		ViewportHeight:     720,
+		DeviceScaleFactor:  2, // Higher quality
+		Format:             FormatPNG,
+		BlockCookieBanners: true,
+	}
+}
+
+// ContentType returns the MIME type of images captured with these options
+func (o Options) ContentType() string {
+	if o.Format == FormatJPEG {
+		return "image/jpeg"
+	}
+	return "image/png"
+}
+
+// validate fills in defaults and rejects unsupported values
+func (o *Options) validate() error {
+	defaults := DefaultOptions()
+	if o.ViewportWidth <= 0 {
+		o.ViewportWidth = defaults.ViewportWidth
+	}
+	if o.ViewportHeight <= 0 {
+		o.ViewportHeight = defaults.ViewportHeight
+	}
+	if o.DeviceScaleFactor <= 0 {
+		o.DeviceScaleFactor = defaults.DeviceScaleFactor
+	}
+	if o.Format == "" {
+		o.Format = defaults.Format
+	}
+	if o.Format == "jpg" {
+		o.Format = FormatJPEG
+	}
+	if o.Format != FormatPNG && o.Format != FormatJPEG {
+		return fmt.Errorf("unsupported screenshot format %q", o.Format)
+	}
+	return nil
+}
+
+// Screenshotter captures an image of a web page
+type Screenshotter interface {
+	// Name returns the provider name
+	Name() string
+	// Capture returns the encoded image bytes of the page at websiteURL
+	Capture(ctx context.Context, websiteURL string, opts Options) ([]byte, error)
+}
+
+// Config selects and configures a provider
+type Config struct {
+	Provider string
+	// ScreenshotOneAPIKey is required by the screenshotone provider
+	ScreenshotOneAPIKey string
+	// ChromePath is the browser binary used by the chrome provider
+	ChromePath string
+}
+
+// New creates the Screenshotter for cfg.Provider (default screenshotone)
+func New(cfg Config) (Screenshotter, error) {
+	switch cfg.Provider {
+	case "", ProviderScreenshotOne:
+		if cfg.ScreenshotOneAPIKey == "" {
+			return nil, fmt.Errorf("SCREENSHOTONE_API_KEY is not set")
+		}
+		return NewScreenshotOne(cfg.ScreenshotOneAPIKey), nil
+	case ProviderChrome:
+		chrome := NewChrome(cfg.ChromePath)
+		if _, err := exec.LookPath(chrome.path); err != nil {
+			return nil, fmt.Errorf("browser %q not found, set CHROME_PATH: %w", chrome.path, err)
+		}
+		return chrome, nil
+	case ProviderFake:
+		return NewFake(), nil
+	default:
+		return nil, fmt.Errorf("unknown screenshot provider %q", cfg.Provider)
+	}
+}
@@ -0,0 +1,83 @@
+package screenshot
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const screenshotOneBaseURL = "https://api.screenshotone.com/take"
+
+// ScreenshotOne captures screenshots through the ScreenshotOne API
+type ScreenshotOne struct {
+	apiKey     string
+	httpClient *http.Client
+}
+
+// NewScreenshotOne creates a new ScreenshotOne provider
+func NewScreenshotOne(apiKey string) *ScreenshotOne {
+	return &ScreenshotOne{
+		apiKey: apiKey,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second, // ScreenshotOne can take a while
+		},
+	}
+}
+
+// Name returns the provider name
+func (s *ScreenshotOne) Name() string {
+	return ProviderScreenshotOne
+}
+
+// Capture captures a screenshot of a website using the ScreenshotOne API
+func (s *ScreenshotOne) Capture(ctx context.Context, websiteURL string, opts Options) ([]byte, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	// Build the ScreenshotOne API URL with query parameters
+	params := url.Values{}
+	params.Add("access_key", s.apiKey)
+	params.Add("url", websiteURL)
+	params.Add("full_page", "false") // Only capture above the fold
+	params.Add("viewport_width", strconv.Itoa(opts.ViewportWidth))
+	params.Add("viewport_height", strconv.Itoa(opts.ViewportHeight))
+	params.Add("device_scale_factor", strconv.FormatFloat(opts.DeviceScaleFactor, 'f', -1, 64))
+	params.Add("format", opts.Format)
+	params.Add("block_cookie_banners", strconv.FormatBool(opts.BlockCookieBanners))
+	if opts.Delay > 0 {
+		params.Add("delay", strconv.Itoa(int(opts.Delay.Seconds())))
+	}
+	for _, selector := range opts.HiddenSelectors {
+		params.Add("hide_selectors", selector)
+	}
+
+	fullURL := fmt.Sprintf("%s?%s", screenshotOneBaseURL, params.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fullURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create screenshot request: %w", err)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call ScreenshotOne API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("ScreenshotOne API returned status %d: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	screenshotBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read screenshot data: %w", err)
+	}
+
+	return screenshotBytes, nil
+}
@@ -0,0 +1,144 @@
+package slugify
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	// MaxLength is the maximum slug length, including any collision suffix
+	MaxLength = 60
+	// maxSuffixAttempts bounds the -2, -3, ... collision search
+	maxSuffixAttempts = 50
+)
+
+// reserved slugs collide with fixed routes under /companies/ or are otherwise confusing
+var reserved = map[string]bool{
+	"admin":       true,
+	"api":         true,
+	"debug":       true,
+	"generate":    true,
+	"latest":      true,
+	"leaderboard": true,
+	"new":         true,
+	"random":      true,
+	"search":      true,
+	"stats":       true,
+	"today":       true,
+}
+
+// IsReserved returns true if the slug cannot be used for a company
+func IsReserved(slug string) bool {
+	return reserved[slug]
+}
+
+// Make creates a URL-friendly slug from a company name
+// Names are transliterated to ASCII, lowercased, non-alphanumerics become hyphens,
+// and the result is capped at MaxLength. Names with no transliterable characters
+// get a stable hash-based slug instead of an empty one.
+func Make(name string) string {
+	var b strings.Builder
+	lastHyphen := true
+	for _, r := range Transliterate(strings.ToLower(name)) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			lastHyphen = false
+			continue
+		}
+		if !lastHyphen {
+			b.WriteByte('-')
+			lastHyphen = true
+		}
+	}
+
+	slug := truncate(strings.Trim(b.String(), "-"), MaxLength)
+	if slug == "" {
+		sum := sha1.Sum([]byte(strings.TrimSpace(name)))
+		slug = "company-" + hex.EncodeToString(sum[:])[:8]
+	}
+	return slug
+}
+
+// Unique returns base, or base with a numeric suffix (-2, -3, ...), such that
+// the result is not reserved and taken reports it as free
+func Unique(base string, taken func(slug string) (bool, error)) (string, error) {
+	for i := 1; i <= maxSuffixAttempts; i++ {
+		candidate := base
+		if i > 1 {
+			suffix := fmt.Sprintf("-%d", i)
+			candidate = truncate(base, MaxLength-len(suffix)) + suffix
+		}
+		if IsReserved(candidate) {
+			continue
+		}
+
+		exists, err := taken(candidate)
+		if err != nil {
+			return "", fmt.Errorf("failed to check slug %q: %w", candidate, err)
+		}
+		if !exists {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("no free slug for %q after %d attempts", base, maxSuffixAttempts)
+}
+
+// Transliterate converts s to ASCII where possible: accents are stripped,
+// ligatures and special Latin letters are expanded, and Cyrillic and Greek are
+// romanized. Characters with no mapping are dropped.
+func Transliterate(s string) string {
+	var b strings.Builder
+	for _, r := range norm.NFKD.String(s) {
+		switch {
+		case r < unicode.MaxASCII:
+			b.WriteRune(r)
+		case unicode.Is(unicode.Mn, r):
+			// Combining marks left over from decomposition (é -> e + ´)
+		default:
+			if mapped, ok := transliterations[unicode.ToLower(r)]; ok {
+				b.WriteString(mapped)
+			} else if unicode.IsSpace(r) || unicode.IsPunct(r) {
+				b.WriteByte(' ')
+			}
+		}
+	}
+	return b.String()
+}
+
+// truncate cuts the slug to max bytes, preferring a hyphen boundary
+func truncate(slug string, max int) string {
+	if len(slug) <= max {
+		return slug
+	}
+	slug = slug[:max]
+	if i := strings.LastIndexByte(slug, '-'); i > max/2 {
+		slug = slug[:i]
+	}
+	return strings.Trim(slug, "-")
+}
+
+// transliterations covers lowercase letters that don't decompose into ASCII under NFKD
+var transliterations = map[rune]string{
+	// Latin
+	'ß': "ss", 'æ': "ae", 'œ': "oe", 'ø': "o", 'ł': "l", 'đ': "d", 'ð': "d",
+	'þ': "th", 'ı': "i", 'ħ': "h", 'ŋ': "ng", 'ĸ': "k",
+
+	// Cyrillic
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo",
+	'ж': "zh", 'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m",
+	'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+	'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch",
+	'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya", 'є': "ye",
+	'і': "i", 'ї': "yi", 'ґ': "g",
+
+	// Greek
+	'α': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'ζ': "z", 'η': "i",
+	'θ': "th", 'ι': "i", 'κ': "k", 'λ': "l", 'μ': "m", 'ν': "n", 'ξ': "x",
+	'ο': "o", 'π': "p", 'ρ': "r", 'σ': "s", 'ς': "s", 'τ': "t", 'υ': "y",
+	'φ': "f", 'χ': "ch", 'ψ': "ps", 'ω': "o",
+}
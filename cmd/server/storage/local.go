@@ -0,0 +1,171 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// AssetsPath is the route under which the API serves locally stored objects
+const AssetsPath = "/assets/"
+
+// LocalStore stores objects on the local filesystem and serves them from the API
+// Intended for development and tests, where no cloud account is available
+type LocalStore struct {
+	root    string
+	baseURL string
+}
+
+// NewLocalStore creates a new filesystem store rooted at dir
+// publicBaseURL is the externally reachable API URL, e.g. http://localhost:8080
+func NewLocalStore(dir, publicBaseURL string) (*LocalStore, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("LOCAL_STORAGE_DIR not provided")
+	}
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("invalid local storage directory: %w", err)
+	}
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create local storage directory: %w", err)
+	}
+
+	return &LocalStore{
+		root:    root,
+		baseURL: strings.TrimRight(publicBaseURL, "/"),
+	}, nil
+}
+
+// pathFor maps a key to a file path inside the root
+// Cleaning against "/" keeps keys like "../x" from escaping the root
+func (s *LocalStore) pathFor(key string) (string, error) {
+	clean := path.Clean("/" + key)
+	if clean == "/" || strings.HasSuffix(key, "/") {
+		return "", fmt.Errorf("invalid object key %q", key)
+	}
+	return filepath.Join(s.root, filepath.FromSlash(clean)), nil
+}
+
+// Put writes an object to disk
+func (s *LocalStore) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	p, err := s.pathFor(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	if err := os.WriteFile(p, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write object: %w", err)
+	}
+	return nil
+}
+
+// Exists checks whether the object file exists
+func (s *LocalStore) Exists(ctx context.Context, key string) (bool, error) {
+	p, err := s.pathFor(key)
+	if err != nil {
+		return false, err
+	}
+	_, err = os.Stat(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to stat object: %w", err)
+	}
+	return true, nil
+}
+
+// Delete removes the object file
+func (s *LocalStore) Delete(ctx context.Context, key string) error {
+	p, err := s.pathFor(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to delete object: %w", err)
+	}
+	return nil
+}
+
+// Move renames the object file
+func (s *LocalStore) Move(ctx context.Context, srcKey, dstKey string) error {
+	src, err := s.pathFor(srcKey)
+	if err != nil {
+		return err
+	}
+	dst, err := s.pathFor(dstKey)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	if err := os.Rename(src, dst); err != nil {
+		return fmt.Errorf("failed to move object: %w", err)
+	}
+	return nil
+}
+
+// List walks the store and returns all objects whose key starts with prefix
+func (s *LocalStore) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+	err := filepath.WalkDir(s.root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(s.root, p)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		objects = append(objects, ObjectInfo{
+			Key:          key,
+			Size:         info.Size(),
+			LastModified: info.ModTime(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list local objects: %w", err)
+	}
+	return objects, nil
+}
+
+// URL returns the API URL serving the object
+func (s *LocalStore) URL(key string) string {
+	return s.baseURL + AssetsPath + key
+}
+
+// SignedURL returns the plain URL, local assets are served without authorization
+func (s *LocalStore) SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	return s.URL(key), nil
+}
+
+// Handler serves stored objects under AssetsPath
+func (s *LocalStore) Handler() http.Handler {
+	return http.StripPrefix(AssetsPath, http.FileServer(http.Dir(s.root)))
+}
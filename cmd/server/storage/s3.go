@@ -2,144 +2,171 @@ package storage
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
-	"path/filepath"
+	"net/url"
 	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
 )
 
-// S3Uploader handles uploading files to S3
-type S3Uploader struct {
-	client     *s3.S3
-	bucketName string
-	region     string
+// S3Store stores objects in an S3 bucket or an S3-compatible service such as MinIO
+type S3Store struct {
+	client         *s3.S3
+	bucketName     string
+	region         string
+	endpoint       string
+	forcePathStyle bool
+	private        bool
 }
 
-// NewS3Uploader creates a new S3 uploader instance
-func NewS3Uploader(region, accessKeyID, secretAccessKey, bucketName string) (*S3Uploader, error) {
-	sess, err := session.NewSession(&aws.Config{
-		Region:      aws.String(region),
-		Credentials: credentials.NewStaticCredentials(accessKeyID, secretAccessKey, ""),
-	})
+// NewS3Store creates a new S3 store
+// Credentials come from the default AWS chain (environment, shared config, task role)
+// Set endpoint (and usually forcePathStyle) to target MinIO or another S3-compatible service
+// Private stores upload without an ACL, which also works on buckets with ACLs disabled
+func NewS3Store(region, bucketName, endpoint string, forcePathStyle, private bool) (*S3Store, error) {
+	awsConfig := &aws.Config{
+		Region:           aws.String(region),
+		S3ForcePathStyle: aws.Bool(forcePathStyle),
+	}
+	if endpoint != "" {
+		awsConfig.Endpoint = aws.String(endpoint)
+	}
+
+	sess, err := session.NewSession(awsConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create AWS session: %w", err)
 	}
 
-	return &S3Uploader{
-		client:     s3.New(sess),
-		bucketName: bucketName,
-		region:     region,
+	return &S3Store{
+		client:         s3.New(sess),
+		bucketName:     bucketName,
+		region:         region,
+		endpoint:       strings.TrimRight(endpoint, "/"),
+		forcePathStyle: forcePathStyle,
+		private:        private,
 	}, nil
 }
 
-// UploadImageFromURL downloads an image from a URL and uploads it to S3
-// Returns the S3 URL of the uploaded image
-func (u *S3Uploader) UploadImageFromURL(imageURL, companySlug string) (string, error) {
-	// Download the image
-	resp, err := http.Get(imageURL)
-	if err != nil {
-		return "", fmt.Errorf("failed to download image: %w", err)
+// Put uploads an object
+func (s *S3Store) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	input := &s3.PutObjectInput{
+		Bucket:       aws.String(s.bucketName),
+		Key:          aws.String(key),
+		Body:         bytes.NewReader(data),
+		ContentType:  aws.String(contentType),
+		CacheControl: aws.String(immutableCacheControl),
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("failed to download image: status code %d", resp.StatusCode)
+	if !s.private {
+		input.ACL = aws.String("public-read") // Make the object publicly accessible
 	}
 
-	// Read the image data
-	imageData, err := io.ReadAll(resp.Body)
+	_, err := s.client.PutObjectWithContext(ctx, input)
 	if err != nil {
-		return "", fmt.Errorf("failed to read image data: %w", err)
-	}
-
-	// Get content type from response header
-	contentType := resp.Header.Get("Content-Type")
-	if contentType == "" {
-		contentType = "application/octet-stream"
+		return fmt.Errorf("failed to upload to S3: %w", err)
 	}
+	return nil
+}
 
-	// Generate a unique filename
-	ext := getExtensionFromURL(imageURL)
-	if ext == "" {
-		ext = getExtensionFromContentType(contentType)
+// Exists checks for an object with a HEAD request
+func (s *S3Store) Exists(ctx context.Context, key string) (bool, error) {
+	_, err := s.client.HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(s.bucketName),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		var reqErr awserr.RequestFailure
+		if errors.As(err, &reqErr) && reqErr.StatusCode() == http.StatusNotFound {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to check S3 object: %w", err)
 	}
-	timestamp := time.Now().Unix()
-	key := fmt.Sprintf("companies/%s/%d%s", companySlug, timestamp, ext)
+	return true, nil
+}
 
-	// Upload to S3
-	_, err = u.client.PutObject(&s3.PutObjectInput{
-		Bucket:      aws.String(u.bucketName),
-		Key:         aws.String(key),
-		Body:        bytes.NewReader(imageData),
-		ContentType: aws.String(contentType),
-		ACL:         aws.String("public-read"), // Make the image publicly accessible
+// Delete removes an object
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	_, err := s.client.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucketName),
+		Key:    aws.String(key),
 	})
 	if err != nil {
-		return "", fmt.Errorf("failed to upload to S3: %w", err)
+		return fmt.Errorf("failed to delete S3 object: %w", err)
+	}
+	return nil
+}
+
+// Move copies an object to dstKey and deletes the original
+func (s *S3Store) Move(ctx context.Context, srcKey, dstKey string) error {
+	input := &s3.CopyObjectInput{
+		Bucket:     aws.String(s.bucketName),
+		Key:        aws.String(dstKey),
+		CopySource: aws.String(url.PathEscape(s.bucketName + "/" + srcKey)),
+	}
+	if !s.private {
+		input.ACL = aws.String("public-read")
 	}
 
-	// Construct the S3 URL
-	s3URL := fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", u.bucketName, u.region, key)
-	return s3URL, nil
+	if _, err := s.client.CopyObjectWithContext(ctx, input); err != nil {
+		return fmt.Errorf("failed to copy S3 object: %w", err)
+	}
+	return s.Delete(ctx, srcKey)
 }
 
-// UploadScreenshot uploads screenshot bytes directly to S3
-// Returns the S3 URL of the uploaded screenshot
-func (u *S3Uploader) UploadScreenshot(screenshotData []byte, companySlug string) (string, error) {
-	// Generate a unique filename for the screenshot
-	timestamp := time.Now().Unix()
-	key := fmt.Sprintf("startup-screenshots/%s-%d.png", companySlug, timestamp)
-
-	// Upload to S3
-	_, err := u.client.PutObject(&s3.PutObjectInput{
-		Bucket:      aws.String(u.bucketName),
-		Key:         aws.String(key),
-		Body:        bytes.NewReader(screenshotData),
-		ContentType: aws.String("image/png"),
-		ACL:         aws.String("public-read"), // Make the screenshot publicly accessible
+// List returns all objects under prefix
+func (s *S3Store) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+	err := s.client.ListObjectsV2PagesWithContext(ctx, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucketName),
+		Prefix: aws.String(prefix),
+	}, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		for _, obj := range page.Contents {
+			objects = append(objects, ObjectInfo{
+				Key:          aws.StringValue(obj.Key),
+				Size:         aws.Int64Value(obj.Size),
+				LastModified: aws.TimeValue(obj.LastModified),
+			})
+		}
+		return true
 	})
 	if err != nil {
-		return "", fmt.Errorf("failed to upload screenshot to S3: %w", err)
+		return nil, fmt.Errorf("failed to list S3 objects: %w", err)
 	}
-
-	// Construct the S3 URL
-	s3URL := fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", u.bucketName, u.region, key)
-	return s3URL, nil
+	return objects, nil
 }
 
-// getExtensionFromURL extracts the file extension from a URL
-func getExtensionFromURL(url string) string {
-	// Remove query parameters
-	if idx := strings.Index(url, "?"); idx != -1 {
-		url = url[:idx]
+// URL returns the public URL of an object
+func (s *S3Store) URL(key string) string {
+	if s.endpoint == "" {
+		return fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", s.bucketName, s.region, key)
 	}
-
-	ext := filepath.Ext(url)
-	if ext != "" {
-		return ext
+	if s.forcePathStyle {
+		return fmt.Sprintf("%s/%s/%s", s.endpoint, s.bucketName, key)
+	}
+	scheme, host, found := strings.Cut(s.endpoint, "://")
+	if !found {
+		scheme, host = "https", s.endpoint
 	}
-	return ""
+	return fmt.Sprintf("%s://%s.%s/%s", scheme, s.bucketName, host, key)
 }
 
-// getExtensionFromContentType returns a file extension based on content type
-func getExtensionFromContentType(contentType string) string {
-	switch contentType {
-	case "image/jpeg", "image/jpg":
-		return ".jpg"
-	case "image/png":
-		return ".png"
-	case "image/gif":
-		return ".gif"
-	case "image/webp":
-		return ".webp"
-	default:
-		return ".jpg" // Default to .jpg
+// SignedURL returns a presigned GET URL
+func (s *S3Store) SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	req, _ := s.client.GetObjectRequest(&s3.GetObjectInput{
+		Bucket: aws.String(s.bucketName),
+		Key:    aws.String(key),
+	})
+	req.SetContext(ctx)
+
+	signed, err := req.Presign(ttl)
+	if err != nil {
+		return "", fmt.Errorf("failed to presign S3 URL: %w", err)
 	}
+	return signed, nil
 }
@@ -0,0 +1,114 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"startupdose.com/cmd/server/config"
+)
+
+// Storage backends selectable with STORAGE_BACKEND
+const (
+	BackendS3       = "s3"
+	BackendSupabase = "supabase"
+	BackendLocal    = "local"
+)
+
+// ObjectInfo describes a stored object
+type ObjectInfo struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+}
+
+// ObjectStore stores binary objects by key and builds their URLs
+type ObjectStore interface {
+	// Put stores data under key, overwriting any existing object
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	// Exists reports whether an object is stored under key
+	Exists(ctx context.Context, key string) (bool, error)
+	// Delete removes the object stored under key
+	Delete(ctx context.Context, key string) error
+	// Move renames an object within the store
+	Move(ctx context.Context, srcKey, dstKey string) error
+	// List returns all objects whose key starts with prefix
+	List(ctx context.Context, prefix string) ([]ObjectInfo, error)
+	// URL returns the canonical URL of the object
+	// Objects in a private store are not fetchable through it; use ResolveURL
+	URL(key string) string
+	// SignedURL returns a URL granting read access to the object for ttl
+	SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error)
+}
+
+var (
+	store     ObjectStore
+	storeOnce sync.Once
+)
+
+// Init creates the object store selected by cfg.StorageBackend
+func Init(cfg *config.Config) error {
+	var err error
+
+	storeOnce.Do(func() {
+		backend := cfg.StorageBackend
+		if backend == "" {
+			backend = BackendS3
+		}
+
+		switch backend {
+		case BackendS3:
+			if cfg.S3BucketName == "" {
+				err = fmt.Errorf("S3_BUCKET_NAME not provided")
+				return
+			}
+			store, err = NewS3Store(cfg.AWSRegion, cfg.S3BucketName, cfg.S3Endpoint, cfg.S3ForcePathStyle, cfg.StoragePrivate)
+		case BackendSupabase:
+			apiKey := cfg.SupabaseServiceRole
+			if apiKey == "" {
+				apiKey = cfg.SupabaseKey
+			}
+			if cfg.SupabaseURL == "" || apiKey == "" || cfg.SupabaseStorageBucket == "" {
+				err = fmt.Errorf("SUPABASE_URL, a Supabase key and SUPABASE_STORAGE_BUCKET are required")
+				return
+			}
+			store = NewSupabaseStore(cfg.SupabaseURL, apiKey, cfg.SupabaseStorageBucket)
+		case BackendLocal:
+			store, err = NewLocalStore(cfg.LocalStorageDir, cfg.PublicBaseURL)
+		default:
+			err = fmt.Errorf("unknown storage backend %q", backend)
+		}
+
+		if err != nil {
+			store = nil
+			return
+		}
+
+		urlCfg, cfgErr := urlConfigFrom(cfg)
+		if cfgErr != nil {
+			log.Printf("WARNING: %v, using defaults\n", cfgErr)
+		}
+		urlConfig = urlCfg
+
+		log.Printf("Object store initialized using %s backend\n", backend)
+	})
+
+	return err
+}
+
+// GetStore returns the configured object store, or nil if none is configured
+func GetStore() ObjectStore {
+	return store
+}
+
+// AssetHandler returns the handler serving locally stored objects, or nil
+// when the active backend serves its own URLs
+func AssetHandler() http.Handler {
+	if local, ok := store.(*LocalStore); ok {
+		return local.Handler()
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	storage_go "github.com/supabase-community/storage-go"
+)
+
+// Supabase Storage listing page size
+const supabaseListLimit = 1000
+
+// SupabaseStore stores objects in a Supabase Storage bucket
+// Public and private buckets are configured in Supabase, not per upload
+type SupabaseStore struct {
+	baseURL string
+	apiKey  string
+	bucket  string
+}
+
+// NewSupabaseStore creates a new Supabase Storage store
+func NewSupabaseStore(supabaseURL, apiKey, bucket string) *SupabaseStore {
+	return &SupabaseStore{
+		baseURL: strings.TrimRight(supabaseURL, "/") + "/storage/v1",
+		apiKey:  apiKey,
+		bucket:  bucket,
+	}
+}
+
+// client returns a fresh storage client
+// storage-go stores per-upload headers (content type, upsert) on the shared
+// transport, so clients are not reused across operations
+func (s *SupabaseStore) client() *storage_go.Client {
+	return storage_go.NewClient(s.baseURL, s.apiKey, map[string]string{"apikey": s.apiKey})
+}
+
+// Put uploads an object, overwriting any existing one
+func (s *SupabaseStore) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	upsert := true
+	cacheControl := "31536000"
+	_, err := s.client().UploadFile(s.bucket, key, bytes.NewReader(data), storage_go.FileOptions{
+		ContentType:  &contentType,
+		CacheControl: &cacheControl,
+		Upsert:       &upsert,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload to Supabase Storage: %w", err)
+	}
+	return nil
+}
+
+// Exists checks for an object with a HEAD request
+func (s *SupabaseStore) Exists(ctx context.Context, key string) (bool, error) {
+	objectURL := fmt.Sprintf("%s/object/%s/%s", s.baseURL, s.bucket, key)
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, objectURL, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+s.apiKey)
+	req.Header.Set("apikey", s.apiKey)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("failed to check Supabase Storage object: %w", err)
+	}
+	resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound, http.StatusBadRequest:
+		// Some Supabase Storage versions report missing objects as 400
+		return false, nil
+	default:
+		return false, fmt.Errorf("Supabase Storage returned status %d", resp.StatusCode)
+	}
+}
+
+// Delete removes an object
+func (s *SupabaseStore) Delete(ctx context.Context, key string) error {
+	if _, err := s.client().RemoveFile(s.bucket, []string{key}); err != nil {
+		return fmt.Errorf("failed to delete Supabase Storage object: %w", err)
+	}
+	return nil
+}
+
+// Move renames an object
+func (s *SupabaseStore) Move(ctx context.Context, srcKey, dstKey string) error {
+	if _, err := s.client().MoveFile(s.bucket, srcKey, dstKey); err != nil {
+		return fmt.Errorf("failed to move Supabase Storage object: %w", err)
+	}
+	return nil
+}
+
+// List returns all objects under prefix, descending into folders
+func (s *SupabaseStore) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	// Supabase lists one folder at a time, so split "folder/name-prefix"
+	folder, namePrefix := "", prefix
+	if i := strings.LastIndex(prefix, "/"); i != -1 {
+		folder, namePrefix = prefix[:i], prefix[i+1:]
+	}
+
+	var objects []ObjectInfo
+	for offset := 0; ; offset += supabaseListLimit {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		files, err := s.client().ListFiles(s.bucket, folder, storage_go.FileSearchOptions{
+			Limit:  supabaseListLimit,
+			Offset: offset,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list Supabase Storage objects: %w", err)
+		}
+
+		for _, f := range files {
+			if !strings.HasPrefix(f.Name, namePrefix) {
+				continue
+			}
+			key := f.Name
+			if folder != "" {
+				key = folder + "/" + f.Name
+			}
+
+			// Folders are listed without an ID
+			if f.Id == "" {
+				nested, err := s.List(ctx, key+"/")
+				if err != nil {
+					return nil, err
+				}
+				objects = append(objects, nested...)
+				continue
+			}
+
+			info := ObjectInfo{Key: key}
+			if t, err := time.Parse(time.RFC3339, f.UpdatedAt); err == nil {
+				info.LastModified = t
+			}
+			if meta, ok := f.Metadata.(map[string]interface{}); ok {
+				if size, ok := meta["size"].(float64); ok {
+					info.Size = int64(size)
+				}
+			}
+			objects = append(objects, info)
+		}
+
+		if len(files) < supabaseListLimit {
+			break
+		}
+	}
+
+	return objects, nil
+}
+
+// URL returns the public URL of an object
+func (s *SupabaseStore) URL(key string) string {
+	return fmt.Sprintf("%s/object/public/%s/%s", s.baseURL, s.bucket, key)
+}
+
+// SignedURL returns a time-limited URL for an object in a private bucket
+func (s *SupabaseStore) SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	resp, err := s.client().CreateSignedUrl(s.bucket, key, int(ttl.Seconds()))
+	if err != nil {
+		return "", fmt.Errorf("failed to sign Supabase Storage URL: %w", err)
+	}
+	return resp.SignedURL, nil
+}
@@ -0,0 +1,217 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"mime"
+	"net/http"
+	"sync"
+
+	_ "golang.org/x/image/webp"
+	"startupdose.com/cmd/server/fetch"
+)
+
+// Objects are keyed by content hash and never rewritten, so they can be cached forever
+const immutableCacheControl = "public, max-age=31536000, immutable"
+
+// Key prefixes per asset source
+const (
+	screenshotPrefix = "startup-screenshots"
+	variantPrefix    = "startup-screenshots/variants"
+	cardPrefix       = "startup-cards"
+	remotePrefix     = "startup-images"
+
+	// Before content addressing, remote images were stored as companies/{slug}/{unix}.ext
+	legacyRemotePrefix = "companies"
+)
+
+// AssetPrefixes lists the key prefixes holding company assets
+var AssetPrefixes = []string{
+	screenshotPrefix + "/",
+	cardPrefix + "/",
+	remotePrefix + "/",
+	legacyRemotePrefix + "/",
+}
+
+// Asset sources recorded in the assets table
+const (
+	SourceScreenshot = "screenshot"
+	SourceVariant    = "variant"
+	SourceCard       = "card"
+	SourceRemote     = "remote"
+)
+
+// Asset describes a stored, content-addressed object
+type Asset struct {
+	Hash        string
+	Key         string
+	URL         string
+	ContentType string
+	Size        int
+	Width       int
+	Height      int
+	Source      string
+	// SourceURL is the URL a remote image was downloaded from
+	SourceURL string
+}
+
+// Uploader stores company assets in an ObjectStore under content-addressed keys
+// (prefix/sha256.ext), skipping uploads of objects that already exist.
+// It remembers every asset it handled so they can be linked to the company afterwards.
+type Uploader struct {
+	store   ObjectStore
+	fetcher *fetch.Fetcher
+
+	mu     sync.Mutex
+	assets []Asset
+}
+
+// NewUploader creates a new uploader backed by store
+// fetcher downloads remote images for UploadImageFromURL
+func NewUploader(store ObjectStore, fetcher *fetch.Fetcher) *Uploader {
+	return &Uploader{store: store, fetcher: fetcher}
+}
+
+// Assets returns every asset stored or reused by this uploader, in upload order
+func (u *Uploader) Assets() []Asset {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return append([]Asset(nil), u.assets...)
+}
+
+// UploadImageFromURL downloads an image from an untrusted URL and stores it
+// The content type is sniffed from the data; anything but a raster image is rejected
+func (u *Uploader) UploadImageFromURL(ctx context.Context, imageURL string) (Asset, error) {
+	if u.fetcher == nil {
+		return Asset{}, fmt.Errorf("remote fetching not configured")
+	}
+
+	resp, err := u.fetcher.Get(ctx, imageURL)
+	if err != nil {
+		return Asset{}, fmt.Errorf("failed to download image: %w", err)
+	}
+
+	// Never trust the server's Content-Type header
+	contentType := resp.ContentType
+	switch contentType {
+	case "image/jpeg", "image/png", "image/gif", "image/webp":
+	default:
+		return Asset{}, fmt.Errorf("unsupported image type %q", contentType)
+	}
+
+	asset, err := u.put(ctx, resp.Body, remotePrefix, getExtensionFromContentType(contentType), contentType, SourceRemote)
+	if err != nil {
+		return Asset{}, err
+	}
+	asset.SourceURL = imageURL
+	u.record(asset)
+	return asset, nil
+}
+
+// UploadScreenshot stores screenshot bytes
+// The content type is detected from the image data (PNG or JPEG)
+func (u *Uploader) UploadScreenshot(ctx context.Context, screenshotData []byte) (Asset, error) {
+	contentType := http.DetectContentType(screenshotData)
+
+	asset, err := u.put(ctx, screenshotData, screenshotPrefix, getExtensionFromContentType(contentType), contentType, SourceScreenshot)
+	if err != nil {
+		return Asset{}, fmt.Errorf("failed to upload screenshot: %w", err)
+	}
+	u.record(asset)
+	return asset, nil
+}
+
+// UploadVariant stores a resized copy of an already uploaded image
+func (u *Uploader) UploadVariant(ctx context.Context, variantData []byte, contentType, ext string) (Asset, error) {
+	asset, err := u.put(ctx, variantData, variantPrefix, ext, contentType, SourceVariant)
+	if err != nil {
+		return Asset{}, fmt.Errorf("failed to upload variant: %w", err)
+	}
+	u.record(asset)
+	return asset, nil
+}
+
+// UploadCard stores a composed social card (PNG)
+func (u *Uploader) UploadCard(ctx context.Context, cardData []byte) (Asset, error) {
+	asset, err := u.put(ctx, cardData, cardPrefix, ".png", "image/png", SourceCard)
+	if err != nil {
+		return Asset{}, fmt.Errorf("failed to upload card: %w", err)
+	}
+	u.record(asset)
+	return asset, nil
+}
+
+// put stores data under prefix/sha256ext unless an object with that key already exists
+func (u *Uploader) put(ctx context.Context, data []byte, prefix, ext, contentType, source string) (Asset, error) {
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	key := fmt.Sprintf("%s/%s%s", prefix, hash, ext)
+
+	asset := Asset{
+		Hash:        hash,
+		Key:         key,
+		URL:         u.store.URL(key),
+		ContentType: contentType,
+		Size:        len(data),
+		Source:      source,
+	}
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		asset.ContentType = mediaType
+	}
+	if cfg, _, err := image.DecodeConfig(bytes.NewReader(data)); err == nil {
+		asset.Width, asset.Height = cfg.Width, cfg.Height
+	}
+
+	exists, err := u.store.Exists(ctx, key)
+	if err != nil {
+		// Uploading again is harmless, the key is derived from the content
+		exists = false
+	}
+	if exists {
+		return asset, nil
+	}
+
+	if err := u.store.Put(ctx, key, data, asset.ContentType); err != nil {
+		return Asset{}, err
+	}
+	return asset, nil
+}
+
+// record remembers an asset for Assets, ignoring repeats of the same hash
+func (u *Uploader) record(asset Asset) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for _, a := range u.assets {
+		if a.Hash == asset.Hash {
+			return
+		}
+	}
+	u.assets = append(u.assets, asset)
+}
+
+// getExtensionFromContentType returns a file extension based on content type
+func getExtensionFromContentType(contentType string) string {
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		contentType = mediaType
+	}
+
+	switch contentType {
+	case "image/jpeg", "image/jpg":
+		return ".jpg"
+	case "image/png":
+		return ".png"
+	case "image/gif":
+		return ".gif"
+	case "image/webp":
+		return ".webp"
+	default:
+		return ".jpg" // Default to .jpg
+	}
+}
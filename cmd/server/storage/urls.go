@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"startupdose.com/cmd/server/config"
+)
+
+// Default lifetime of presigned URLs
+const defaultSignedURLTTL = time.Hour
+
+// URLConfig controls how stored objects are exposed to clients
+type URLConfig struct {
+	// CDNBaseURL, when set, serves objects as CDNBaseURL/key
+	CDNBaseURL string
+	// Private serves objects through presigned URLs when no CDN is configured
+	Private bool
+	// SignedURLTTL is the lifetime of presigned URLs
+	SignedURLTTL time.Duration
+}
+
+var urlConfig = URLConfig{SignedURLTTL: defaultSignedURLTTL}
+
+// urlConfigFrom reads the URL settings from cfg
+func urlConfigFrom(cfg *config.Config) (URLConfig, error) {
+	urlCfg := URLConfig{
+		CDNBaseURL:   strings.TrimRight(cfg.StorageCDNBaseURL, "/"),
+		Private:      cfg.StoragePrivate,
+		SignedURLTTL: defaultSignedURLTTL,
+	}
+
+	if cfg.StorageSignedURLTTL != "" {
+		ttl, err := time.ParseDuration(cfg.StorageSignedURLTTL)
+		if err != nil || ttl <= 0 {
+			return urlCfg, fmt.Errorf("invalid STORAGE_SIGNED_URL_TTL %q", cfg.StorageSignedURLTTL)
+		}
+		urlCfg.SignedURLTTL = ttl
+	}
+
+	return urlCfg, nil
+}
+
+// KeyFromURL returns the object key of a URL built by the configured store
+// Reports false for URLs pointing elsewhere, such as AI-provided images
+func KeyFromURL(rawURL string) (string, bool) {
+	if store == nil || rawURL == "" {
+		return "", false
+	}
+
+	base := store.URL("")
+	if !strings.HasPrefix(rawURL, base) {
+		return "", false
+	}
+
+	key := strings.TrimPrefix(rawURL, base)
+	if i := strings.IndexAny(key, "?#"); i != -1 {
+		key = key[:i]
+	}
+	return key, key != ""
+}
+
+// ResolveURL turns a stored object URL into one clients can fetch: the CDN URL
+// when a CDN is configured, a presigned URL for private stores, or the URL unchanged
+// URLs not served by the store are returned as-is
+func ResolveURL(ctx context.Context, rawURL string) string {
+	key, ok := KeyFromURL(rawURL)
+	if !ok {
+		return rawURL
+	}
+
+	if urlConfig.CDNBaseURL != "" {
+		return urlConfig.CDNBaseURL + "/" + key
+	}
+
+	if urlConfig.Private {
+		signed, err := store.SignedURL(ctx, key, urlConfig.SignedURLTTL)
+		if err != nil {
+			log.Printf("WARNING: Failed to sign URL for %s: %v\n", key, err)
+			return rawURL
+		}
+		return signed
+	}
+
+	return rawURL
+}
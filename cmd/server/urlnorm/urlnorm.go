@@ -0,0 +1,161 @@
+package urlnorm
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/idna"
+	"golang.org/x/net/publicsuffix"
+)
+
+// trackingParams are query parameters removed during normalization
+var trackingParams = map[string]bool{
+	"_ga":     true,
+	"_gl":     true,
+	"dclid":   true,
+	"fbclid":  true,
+	"gbraid":  true,
+	"gclid":   true,
+	"igshid":  true,
+	"mc_cid":  true,
+	"mc_eid":  true,
+	"msclkid": true,
+	"ref":     true,
+	"ref_src": true,
+	"si":      true,
+	"wbraid":  true,
+	"yclid":   true,
+}
+
+// trackingPrefixes are query parameter prefixes removed during normalization
+var trackingPrefixes = []string{"utm_", "hsa_", "pk_", "mtm_"}
+
+// Website is a canonicalized website URL
+type Website struct {
+	// Scheme is "https" or "http"
+	Scheme string
+	// Host is the lowercased ASCII (punycode) host without a leading "www."
+	Host string
+	// URL is the canonical URL without scheme, e.g. "foo.com/pricing"
+	URL string
+	// Domain is the registrable domain (eTLD+1), e.g. "foo.co.uk"
+	Domain string
+	// Link is the cleaned URL with scheme and the host as given, "www." kept,
+	// for fetching and linking; hosts without "www." may not resolve
+	Link string
+}
+
+// String returns the canonical URL including the scheme
+func (w *Website) String() string {
+	return w.Scheme + "://" + w.URL
+}
+
+// Normalize canonicalizes a website URL so equivalent inputs compare equal:
+// the host is lowercased, converted to ASCII and stripped of "www." (Link
+// keeps it), default ports, fragments, tracking parameters and trailing
+// slashes are removed, and the remaining query parameters are sorted. URLs
+// without a scheme are assumed to be https.
+func Normalize(raw string) (*Website, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, fmt.Errorf("empty URL")
+	}
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL %q: %w", raw, err)
+	}
+
+	scheme := strings.ToLower(u.Scheme)
+	if scheme != "http" && scheme != "https" {
+		return nil, fmt.Errorf("unsupported URL scheme %q", u.Scheme)
+	}
+
+	linkHost, err := normalizeHost(u.Hostname())
+	if err != nil {
+		return nil, err
+	}
+	host := strings.TrimPrefix(linkHost, "www.")
+
+	port := u.Port()
+	if (scheme == "https" && port == "443") || (scheme == "http" && port == "80") {
+		port = ""
+	}
+	hostPort, linkHostPort := host, linkHost
+	if port != "" {
+		hostPort = net.JoinHostPort(host, port)
+		linkHostPort = net.JoinHostPort(linkHost, port)
+	}
+
+	path := strings.TrimRight(u.EscapedPath(), "/")
+
+	query := u.Query()
+	for key := range query {
+		if isTrackingParam(key) {
+			query.Del(key)
+		}
+	}
+
+	pathQuery := path
+	if encoded := query.Encode(); encoded != "" {
+		pathQuery += "?" + encoded
+	}
+
+	return &Website{
+		Scheme: scheme,
+		Host:   host,
+		URL:    hostPort + pathQuery,
+		Domain: registrableDomain(host),
+		Link:   scheme + "://" + linkHostPort + pathQuery,
+	}, nil
+}
+
+// normalizeHost lowercases the host and converts IDNs to punycode
+func normalizeHost(host string) (string, error) {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "" {
+		return "", fmt.Errorf("URL has no host")
+	}
+
+	if net.ParseIP(host) == nil {
+		ascii, err := idna.Lookup.ToASCII(host)
+		if err != nil {
+			return "", fmt.Errorf("invalid host %q: %w", host, err)
+		}
+		host = ascii
+	}
+
+	return host, nil
+}
+
+// registrableDomain returns the eTLD+1 for host, or host itself for IPs and
+// hosts without a known public suffix
+func registrableDomain(host string) string {
+	if net.ParseIP(host) != nil {
+		return host
+	}
+	domain, err := publicsuffix.EffectiveTLDPlusOne(host)
+	if err != nil {
+		return host
+	}
+	return domain
+}
+
+// isTrackingParam returns true if the query parameter only carries tracking data
+func isTrackingParam(key string) bool {
+	key = strings.ToLower(key)
+	if trackingParams[key] {
+		return true
+	}
+	for _, prefix := range trackingPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,11 @@
+-- ============================================================================
+-- Company Category and Keywords
+-- ============================================================================
+-- Generated alongside each company and turned into the hashtags of its
+-- Instagram post, so captions carry tags relevant to the startup instead of
+-- the same fixed set every day.
+-- ============================================================================
+
+ALTER TABLE public.companies
+    ADD COLUMN IF NOT EXISTS category text,
+    ADD COLUMN IF NOT EXISTS keywords text[] NOT NULL DEFAULT '{}';
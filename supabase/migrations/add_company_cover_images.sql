@@ -0,0 +1,10 @@
+-- ============================================================================
+-- Responsive Cover Images
+-- ============================================================================
+-- cover_images holds every rendition of the cover image as
+-- [{"url": "...", "width": 640, "height": 360, "format": "webp"}, ...]
+-- so the frontend can build srcset attributes. cover_image keeps the original.
+-- ============================================================================
+
+ALTER TABLE public.companies
+    ADD COLUMN IF NOT EXISTS cover_images jsonb NOT NULL DEFAULT '[]'::jsonb;
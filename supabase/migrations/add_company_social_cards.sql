@@ -0,0 +1,11 @@
+-- ============================================================================
+-- Company Social Cards
+-- ============================================================================
+-- Branded 1080x1080 and 1080x1350 cards composed from the website screenshot.
+-- The raw screenshot stays in cover_image; the portrait card is what gets
+-- posted to Instagram.
+-- ============================================================================
+
+ALTER TABLE public.companies
+    ADD COLUMN IF NOT EXISTS square_card_image text,
+    ADD COLUMN IF NOT EXISTS portrait_card_image text;
@@ -0,0 +1,33 @@
+-- ============================================================================
+-- Canonical Company Websites
+-- ============================================================================
+-- companies.website now stores a canonical URL without scheme (lowercased
+-- ASCII host without "www.", no tracking parameters, fragment or trailing
+-- slash). The scheme is kept separately and the registrable domain (eTLD+1)
+-- is stored for deduplication and search.
+-- ============================================================================
+
+ALTER TABLE public.companies
+    ADD COLUMN IF NOT EXISTS website_scheme text NOT NULL DEFAULT 'https',
+    ADD COLUMN IF NOT EXISTS domain text NOT NULL DEFAULT '';
+
+-- ----------------------------------------------------------------------------
+-- Backfill existing rows
+-- ----------------------------------------------------------------------------
+-- This approximates the Go normalizer: it lowercases the host, strips "www."
+-- and trailing slashes, and uses the host as the domain. Multi-part public
+-- suffixes (e.g. .co.uk) and subdomains are not reduced to eTLD+1 here.
+UPDATE public.companies
+SET website = regexp_replace(
+        regexp_replace(lower(split_part(website, '/', 1)), '^www\.', '')
+            || substr(website, length(split_part(website, '/', 1)) + 1),
+        '/+$', ''
+    )
+WHERE website <> '';
+
+UPDATE public.companies
+SET domain = split_part(split_part(website, '/', 1), '?', 1)
+WHERE domain = '' AND website <> '';
+
+CREATE INDEX IF NOT EXISTS companies_domain_idx
+    ON public.companies (domain);
@@ -0,0 +1,11 @@
+-- ============================================================================
+-- Company Website Links
+-- ============================================================================
+-- companies.website is canonical and drops "www.", but some sites only answer
+-- on their www host. website_link keeps the cleaned URL with scheme and the
+-- host as generated, and is used when screenshotting or linking to a stored
+-- company. Rows without one fall back to website_scheme + website.
+-- ============================================================================
+
+ALTER TABLE public.companies
+    ADD COLUMN IF NOT EXISTS website_link text NOT NULL DEFAULT '';
@@ -0,0 +1,69 @@
+-- ============================================================================
+-- Weekly Orphaned Asset Garbage Collection
+-- ============================================================================
+-- Failed generation runs leave screenshots, variants and cards in storage
+-- that no company references. This job calls POST /admin/assets/gc every
+-- Sunday, which moves unreferenced objects older than ASSET_GC_GRACE_PERIOD
+-- under the quarantine prefix (or deletes them when ASSET_GC_MODE=delete).
+--
+-- The same collection can be run by hand with:
+--   server gc assets -dry-run
+-- ============================================================================
+
+-- Extensions are enabled by create_daily_companies_cron.sql
+CREATE EXTENSION IF NOT EXISTS pg_cron;
+CREATE EXTENSION IF NOT EXISTS pg_net;
+
+-- ----------------------------------------------------------------------------
+-- 1. Create function to call the assets/gc API endpoint
+-- ----------------------------------------------------------------------------
+CREATE OR REPLACE FUNCTION public.call_assets_gc()
+RETURNS bigint
+LANGUAGE plpgsql
+SECURITY DEFINER
+AS $$
+DECLARE
+    v_request_id bigint;
+BEGIN
+    -- NOTE: Replace 'REPLACE_WITH_SECRET' with your actual API key, or read it
+    -- from Supabase Vault as described in create_daily_companies_cron.sql
+    SELECT INTO v_request_id net.http_post(
+        url := 'https://api.startupdose.com/admin/assets/gc?dry_run=false',
+        headers := jsonb_build_object(
+            'Content-Type', 'application/json',
+            'x-api-key', 'REPLACE_WITH_SECRET'
+        ),
+        body := '{}'::jsonb,
+        -- Listing a large bucket can take a while
+        timeout_milliseconds := 60000
+    );
+
+    RAISE NOTICE 'Asset GC request initiated with ID: %', v_request_id;
+    RETURN v_request_id;
+END;
+$$;
+
+COMMENT ON FUNCTION public.call_assets_gc() IS
+'Calls the backend API to collect orphaned assets. Returns the pg_net request ID; the report is in net._http_response.';
+
+-- ----------------------------------------------------------------------------
+-- 2. Schedule the cron job for Sundays at 06:00 UTC
+-- ----------------------------------------------------------------------------
+-- Runs an hour after the daily generation job so in-flight uploads are done
+SELECT cron.schedule(
+    'weekly_assets_gc',                   -- Job name
+    '0 6 * * 0',                          -- Sundays at 06:00 UTC
+    $$SELECT public.call_assets_gc();$$
+);
+
+-- ----------------------------------------------------------------------------
+-- 3. Manual testing
+-- ----------------------------------------------------------------------------
+-- Trigger a run immediately:
+--   SELECT public.call_assets_gc();
+--
+-- Inspect the report (replace <id> with the returned request ID):
+--   SELECT status_code, content FROM net._http_response WHERE id = <id>;
+--
+-- To unschedule the job (if needed):
+--   SELECT cron.unschedule('weekly_assets_gc');
@@ -0,0 +1,32 @@
+-- ============================================================================
+-- Company Assets
+-- ============================================================================
+-- Screenshots, responsive variants, social cards and mirrored images are
+-- stored under content-addressed keys ({prefix}/{sha256}.{ext}), so identical
+-- uploads share one object. This table records which objects each company
+-- uses, with their dimensions, MIME type and where they came from.
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS public.assets (
+    id         uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    company_id uuid NOT NULL REFERENCES public.companies (id) ON DELETE CASCADE,
+    -- Hex-encoded SHA-256 of the object content
+    hash       text NOT NULL,
+    key        text NOT NULL,
+    mime_type  text NOT NULL,
+    size_bytes integer NOT NULL DEFAULT 0,
+    width      integer,
+    height     integer,
+    -- screenshot | variant | card | remote
+    source     text NOT NULL,
+    -- Original URL for remote images
+    source_url text,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    UNIQUE (company_id, hash)
+);
+
+CREATE INDEX IF NOT EXISTS assets_hash_idx
+    ON public.assets (hash);
+
+COMMENT ON TABLE public.assets IS
+'Content-addressed objects in storage and the companies that use them.';
@@ -0,0 +1,33 @@
+-- ============================================================================
+-- Company Candidates Backlog
+-- ============================================================================
+-- POST /companies/generate asks the LLM for several candidates, scores them,
+-- and features the best one. The remaining candidates are stored here so they
+-- can be reviewed and featured on a later day.
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS public.company_candidates (
+    id                uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    name              text NOT NULL,
+    slug              text NOT NULL,
+    website           text NOT NULL DEFAULT '',
+    cover_image       text NOT NULL DEFAULT '',
+    description       text NOT NULL DEFAULT '',
+    appeal            text NOT NULL DEFAULT '',
+    twitter           text NOT NULL DEFAULT '',
+    linkedin          text NOT NULL DEFAULT '',
+    facebook          text NOT NULL DEFAULT '',
+    instagram         text NOT NULL DEFAULT '',
+    score             numeric(8, 3) NOT NULL DEFAULT 0,
+    checks            jsonb NOT NULL DEFAULT '{}'::jsonb,
+    -- backlog | duplicate
+    status            text NOT NULL DEFAULT 'backlog',
+    generation_params jsonb,
+    created_at        timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS company_candidates_status_score_idx
+    ON public.company_candidates (status, score DESC);
+
+COMMENT ON TABLE public.company_candidates IS
+'Generated startups that lost best-of-N selection; status=backlog entries can be featured later.';
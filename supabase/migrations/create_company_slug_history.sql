@@ -0,0 +1,63 @@
+-- ============================================================================
+-- Company Slug History
+-- ============================================================================
+-- Slugs are unique across current and previous values. When a company's slug
+-- changes, the old slug is recorded here so GET /companies/{slug} can redirect
+-- old URLs to the company's current slug.
+-- ============================================================================
+
+-- ----------------------------------------------------------------------------
+-- 1. Enforce unique current slugs
+-- ----------------------------------------------------------------------------
+CREATE UNIQUE INDEX IF NOT EXISTS companies_slug_unique_idx
+    ON public.companies (slug);
+
+-- ----------------------------------------------------------------------------
+-- 2. History of previous slugs
+-- ----------------------------------------------------------------------------
+CREATE TABLE IF NOT EXISTS public.company_slug_history (
+    id         uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    company_id uuid NOT NULL REFERENCES public.companies (id) ON DELETE CASCADE,
+    slug       text NOT NULL UNIQUE,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS company_slug_history_company_id_idx
+    ON public.company_slug_history (company_id);
+
+-- ----------------------------------------------------------------------------
+-- 3. Record the old slug whenever a company is renamed
+-- ----------------------------------------------------------------------------
+CREATE OR REPLACE FUNCTION public.record_company_slug_change()
+RETURNS trigger
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    IF NEW.slug IS DISTINCT FROM OLD.slug THEN
+        -- Another company's old slug still redirects to it; taking it over
+        -- would break those links (the API checks this with SlugTaken)
+        IF EXISTS (
+            SELECT 1 FROM public.company_slug_history
+            WHERE slug = NEW.slug AND company_id <> NEW.id
+        ) THEN
+            RAISE EXCEPTION 'slug "%" was previously used by another company', NEW.slug
+                USING ERRCODE = 'unique_violation';
+        END IF;
+
+        INSERT INTO public.company_slug_history (company_id, slug)
+        VALUES (OLD.id, OLD.slug)
+        ON CONFLICT (slug) DO NOTHING;
+
+        -- A company renamed back to one of its own old slugs no longer needs the redirect
+        DELETE FROM public.company_slug_history
+        WHERE slug = NEW.slug AND company_id = NEW.id;
+    END IF;
+    RETURN NEW;
+END;
+$$;
+
+DROP TRIGGER IF EXISTS companies_slug_history_trigger ON public.companies;
+CREATE TRIGGER companies_slug_history_trigger
+    AFTER UPDATE OF slug ON public.companies
+    FOR EACH ROW
+    EXECUTE FUNCTION public.record_company_slug_change();
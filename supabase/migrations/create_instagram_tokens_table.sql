@@ -0,0 +1,33 @@
+-- ============================================================================
+-- Instagram Access Tokens
+-- ============================================================================
+-- Long-lived Instagram tokens expire after 60 days. The API seeds this table
+-- from IG_ACCESS_TOKEN, refreshes the stored token before it expires and
+-- records expiry and the outcome of every check, so a failing refresh shows
+-- up in /readyz and notifications instead of as silently failing posts.
+--
+-- Tokens are secrets: RLS is enabled without policies so only the service
+-- role can read them.
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS public.instagram_tokens (
+    id                     uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    ig_user_id             text NOT NULL UNIQUE,
+    access_token           text NOT NULL,
+    -- NULL for tokens that never expire
+    expires_at             timestamptz,
+    data_access_expires_at timestamptz,
+    scopes                 text[] NOT NULL DEFAULT '{}',
+    is_valid               boolean NOT NULL DEFAULT true,
+    last_refreshed_at      timestamptz,
+    last_checked_at        timestamptz,
+    -- Error from the most recent debug or refresh, cleared on success
+    last_error             text,
+    created_at             timestamptz NOT NULL DEFAULT now(),
+    updated_at             timestamptz NOT NULL DEFAULT now()
+);
+
+ALTER TABLE public.instagram_tokens ENABLE ROW LEVEL SECURITY;
+
+COMMENT ON TABLE public.instagram_tokens IS
+'Current Instagram Graph API token per account, refreshed automatically before expiry.';
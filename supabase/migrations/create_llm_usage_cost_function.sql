@@ -0,0 +1,22 @@
+-- ============================================================================
+-- LLM Spend Totals
+-- ============================================================================
+-- Sums llm_usage.cost_usd in the database. Reading the rows and adding them
+-- up in the API undercounts once a period has more calls than PostgREST's
+-- max-rows, which would let spend run past the caps.
+--
+-- Called as POST /rest/v1/rpc/llm_usage_cost_since {"since": "..."}.
+-- ============================================================================
+
+CREATE OR REPLACE FUNCTION public.llm_usage_cost_since(since timestamptz)
+RETURNS numeric
+LANGUAGE sql
+STABLE
+AS $$
+    SELECT COALESCE(sum(cost_usd), 0)
+    FROM public.llm_usage
+    WHERE created_at >= since;
+$$;
+
+COMMENT ON FUNCTION public.llm_usage_cost_since(timestamptz) IS
+'Total LLM cost in USD of calls made at or after since, used for the spend caps.';
@@ -0,0 +1,24 @@
+-- ============================================================================
+-- LLM Usage Tracking
+-- ============================================================================
+-- Records token usage and cost for every LLM call so the API can enforce
+-- daily and monthly spend caps on POST /companies/generate.
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS public.llm_usage (
+    id                uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    model             text NOT NULL,
+    purpose           text NOT NULL DEFAULT '',
+    prompt_tokens     integer NOT NULL DEFAULT 0,
+    completion_tokens integer NOT NULL DEFAULT 0,
+    total_tokens      integer NOT NULL DEFAULT 0,
+    cost_usd          numeric(12, 6) NOT NULL DEFAULT 0,
+    created_at        timestamptz NOT NULL DEFAULT now()
+);
+
+-- Spend queries always filter by created_at
+CREATE INDEX IF NOT EXISTS llm_usage_created_at_idx
+    ON public.llm_usage (created_at);
+
+COMMENT ON TABLE public.llm_usage IS
+'One row per LLM call with token counts and USD cost computed from LLM_PRICE_TABLE.';
@@ -0,0 +1,64 @@
+-- ============================================================================
+-- Social Post Insights
+-- ============================================================================
+-- Time series of Instagram metrics for posted feed posts. The API fetches the
+-- lifetime metrics of recent posts periodically and appends a snapshot each
+-- time, so GET /companies/{id}/stats can show how a post's reach grew and
+-- GET /companies/leaderboard can rank featured startups by their latest
+-- numbers.
+--
+-- impressions holds the views metric on Graph API v22+, where Meta retired
+-- impressions.
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS public.social_post_insights (
+    id             uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    social_post_id uuid NOT NULL REFERENCES public.social_posts (id) ON DELETE CASCADE,
+    company_id     uuid NOT NULL REFERENCES public.companies (id) ON DELETE CASCADE,
+    media_id       text NOT NULL,
+    reach          integer NOT NULL DEFAULT 0,
+    impressions    integer NOT NULL DEFAULT 0,
+    likes          integer NOT NULL DEFAULT 0,
+    comments       integer NOT NULL DEFAULT 0,
+    saves          integer NOT NULL DEFAULT 0,
+    fetched_at     timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS social_post_insights_post_fetched_idx
+    ON public.social_post_insights (social_post_id, fetched_at DESC);
+
+CREATE INDEX IF NOT EXISTS social_post_insights_company_idx
+    ON public.social_post_insights (company_id, fetched_at);
+
+COMMENT ON TABLE public.social_post_insights IS
+'Snapshots of Instagram metrics per posted social post, appended by the insights collector.';
+
+-- ============================================================================
+-- Latest snapshot per post, with the company it features
+-- ============================================================================
+-- engagement is likes + comments + saves, the default leaderboard ranking.
+
+CREATE OR REPLACE VIEW public.social_post_latest_insights AS
+SELECT DISTINCT ON (i.social_post_id)
+    i.social_post_id,
+    i.company_id,
+    c.name AS company_name,
+    c.slug AS company_slug,
+    p.channel,
+    i.media_id,
+    p.permalink,
+    p.posted_at,
+    i.reach,
+    i.impressions,
+    i.likes,
+    i.comments,
+    i.saves,
+    i.likes + i.comments + i.saves AS engagement,
+    i.fetched_at
+FROM public.social_post_insights i
+JOIN public.social_posts p ON p.id = i.social_post_id
+JOIN public.companies c ON c.id = i.company_id
+ORDER BY i.social_post_id, i.fetched_at DESC;
+
+COMMENT ON VIEW public.social_post_latest_insights IS
+'Most recent insights snapshot per social post, used for the leaderboard.';
@@ -0,0 +1,39 @@
+-- ============================================================================
+-- Social Posts
+-- ============================================================================
+-- One row per company and channel (instagram feed, instagram story) recording
+-- the outcome of publishing it. The unique (company_id, channel) constraint is
+-- the idempotency key: a task must insert the row (status 'posting') before
+-- publishing, so a retried generate request or a second ECS task racing on
+-- the same company finds the existing row and never posts twice.
+--
+-- status: posting -> posted | failed
+-- A row stuck in 'posting' means a task died mid-publish; it is left for an
+-- operator to check on Instagram rather than re-posted automatically.
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS public.social_posts (
+    id           uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    company_id   uuid NOT NULL REFERENCES public.companies (id) ON DELETE CASCADE,
+    channel      text NOT NULL,
+    status       text NOT NULL DEFAULT 'posting'
+                 CHECK (status IN ('posting', 'posted', 'failed')),
+    container_id text,
+    media_id     text,
+    permalink    text,
+    error        text,
+    attempts     integer NOT NULL DEFAULT 1,
+    -- Host that claimed the latest attempt
+    claimed_by   text,
+    claimed_at   timestamptz NOT NULL DEFAULT now(),
+    posted_at    timestamptz,
+    created_at   timestamptz NOT NULL DEFAULT now(),
+    updated_at   timestamptz NOT NULL DEFAULT now(),
+    UNIQUE (company_id, channel)
+);
+
+CREATE INDEX IF NOT EXISTS social_posts_status_idx
+    ON public.social_posts (status);
+
+COMMENT ON TABLE public.social_posts IS
+'Publishing outcome per company and social channel; unique per (company_id, channel) so posting is idempotent.';